@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// textEncoding is a Unicode encoding of a document.
+type textEncoding uint8
+
+const (
+	encodingUTF8 textEncoding = iota
+	encodingUTF16BE
+	encodingUTF16LE
+	encodingUTF32BE
+	encodingUTF32LE
+)
+
+func (e textEncoding) String() string {
+	switch e {
+	case encodingUTF16BE:
+		return "UTF-16BE"
+	case encodingUTF16LE:
+		return "UTF-16LE"
+	case encodingUTF32BE:
+		return "UTF-32BE"
+	case encodingUTF32LE:
+		return "UTF-32LE"
+	default:
+		return "UTF-8"
+	}
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF32BE = []byte{0x00, 0x00, 0xFE, 0xFF}
+	bomUTF32LE = []byte{0xFF, 0xFE, 0x00, 0x00}
+)
+
+// ToUTF8 converts a JSON or YAML document to UTF-8.
+//
+// The encoding of the document is detected, in this order:
+//   - from a byte order mark (BOM), which is removed: UTF-8, UTF-16 or UTF-32, big or little endian
+//   - from the pattern of null bytes in the first 4 bytes, assuming that the document starts with
+//     2 ASCII characters, as suggested by RFC 8259 and RFC 4627. UTF-32 also requires a null byte
+//     in the second character, so that UTF-8 text padded with null bytes is not mistaken for UTF-32
+//   - UTF-8 is assumed otherwise
+//
+// A document in UTF-8 without BOM is returned unchanged. Invalid UTF-16 or UTF-32 characters are
+// replaced by the Unicode replacement character U+FFFD.
+//
+// An error is returned if the document is truncated, e.g. an odd number of bytes with a UTF-16 BOM.
+func ToUTF8(data []byte) ([]byte, error) {
+	encoding, bomSize := detectEncoding(data)
+
+	switch encoding {
+	case encodingUTF8:
+		return data[bomSize:], nil
+	case encodingUTF16BE, encodingUTF16LE:
+		return decodeUTF16(data[bomSize:], encoding)
+	default:
+		return decodeUTF32(data[bomSize:], encoding)
+	}
+}
+
+// detectEncoding detects the encoding of a document, and the size of its byte order mark, if any.
+func detectEncoding(data []byte) (textEncoding, int) {
+	// the UTF-32LE BOM starts like the UTF-16LE BOM, and must be checked first
+	for _, candidate := range []struct {
+		bom      []byte
+		encoding textEncoding
+	}{
+		{bom: bomUTF8, encoding: encodingUTF8},
+		{bom: bomUTF32BE, encoding: encodingUTF32BE},
+		{bom: bomUTF32LE, encoding: encodingUTF32LE},
+		{bom: bomUTF16BE, encoding: encodingUTF16BE},
+		{bom: bomUTF16LE, encoding: encodingUTF16LE},
+	} {
+		if bytes.HasPrefix(data, candidate.bom) {
+			return candidate.encoding, len(candidate.bom)
+		}
+	}
+
+	const minSize = 4
+	if len(data) < minSize {
+		return encodingUTF8, 0
+	}
+
+	switch nulls := [minSize]bool{data[0] == 0, data[1] == 0, data[2] == 0, data[3] == 0}; nulls {
+	case [minSize]bool{true, true, true, false}:
+		// the high byte of the second character is null too, unlike UTF-8 text padded with null bytes
+		if len(data)%4 == 0 && (len(data) == minSize || data[4] == 0) {
+			return encodingUTF32BE, 0
+		}
+	case [minSize]bool{false, true, true, true}:
+		if len(data)%4 == 0 && (len(data) == minSize || data[7] == 0) {
+			return encodingUTF32LE, 0
+		}
+	case [minSize]bool{true, false, true, false}:
+		if len(data)%2 == 0 {
+			return encodingUTF16BE, 0
+		}
+	case [minSize]bool{false, true, false, true}:
+		if len(data)%2 == 0 {
+			return encodingUTF16LE, 0
+		}
+	}
+
+	return encodingUTF8, 0
+}
+
+func decodeUTF16(data []byte, encoding textEncoding) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("truncated %v document: %d bytes: %w", encoding, len(data), ErrJSON)
+	}
+
+	order := byteOrder(encoding)
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		units = append(units, order.Uint16(data[i:]))
+	}
+
+	decoded := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		decoded = utf8.AppendRune(decoded, r)
+	}
+
+	return decoded, nil
+}
+
+func decodeUTF32(data []byte, encoding textEncoding) ([]byte, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("truncated %v document: %d bytes: %w", encoding, len(data), ErrJSON)
+	}
+
+	order := byteOrder(encoding)
+	decoded := make([]byte, 0, len(data)/4)
+	for i := 0; i < len(data); i += 4 {
+		r := rune(order.Uint32(data[i:])) //nolint:gosec // invalid runes are replaced by utf8.AppendRune
+		decoded = utf8.AppendRune(decoded, r)
+	}
+
+	return decoded, nil
+}
+
+func byteOrder(encoding textEncoding) binary.ByteOrder {
+	if encoding == encodingUTF16BE || encoding == encodingUTF32BE {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestToUTF8(t *testing.T) {
+	const doc = `{"name":"naïve 🐕"}`
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "UTF-8", data: []byte(doc)},
+		{name: "UTF-8 with BOM", data: append([]byte{0xEF, 0xBB, 0xBF}, doc...)},
+		{name: "UTF-16BE with BOM", data: encodeUTF16(binary.BigEndian, "\ufeff"+doc)},
+		{name: "UTF-16LE with BOM", data: encodeUTF16(binary.LittleEndian, "\ufeff"+doc)},
+		{name: "UTF-16BE", data: encodeUTF16(binary.BigEndian, doc)},
+		{name: "UTF-16LE", data: encodeUTF16(binary.LittleEndian, doc)},
+		{name: "UTF-32BE with BOM", data: encodeUTF32(binary.BigEndian, "\ufeff"+doc)},
+		{name: "UTF-32LE with BOM", data: encodeUTF32(binary.LittleEndian, "\ufeff"+doc)},
+		{name: "UTF-32BE", data: encodeUTF32(binary.BigEndian, doc)},
+		{name: "UTF-32LE", data: encodeUTF32(binary.LittleEndian, doc)},
+	} {
+		t.Run("should convert "+tc.name, func(t *testing.T) {
+			decoded, err := ToUTF8(tc.data)
+			require.NoError(t, err)
+			assert.EqualT(t, doc, string(decoded))
+
+			t.Run("should read JSON", func(t *testing.T) {
+				var value JSONMapSlice
+				require.NoError(t, ReadJSON(tc.data, &value))
+				assert.Equal(t, JSONMapSlice{{Key: "name", Value: "naïve 🐕"}}, value)
+			})
+		})
+	}
+
+	t.Run("should leave short documents unchanged", func(t *testing.T) {
+		for _, data := range [][]byte{nil, []byte("1"), []byte("{}"), {'1', 0, 0}} {
+			decoded, err := ToUTF8(data)
+			require.NoError(t, err)
+			assert.Equal(t, data, decoded)
+		}
+	})
+
+	t.Run("should leave null bytes that don't match an encoding", func(t *testing.T) {
+		data := []byte("\x00\x00{}\x00")
+
+		decoded, err := ToUTF8(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded)
+
+		var value JSONMapSlice
+		require.NoError(t, ReadJSON(data, &value))
+		assert.Empty(t, value)
+	})
+
+	t.Run("should read UTF-8 padded with null bytes", func(t *testing.T) {
+		for _, data := range [][]byte{
+			[]byte("\x00\x00\x00{\"a\":123}"),
+			[]byte("{\"a\":123}\x00\x00\x00"),
+			[]byte("\x00\x00\x00\x00\x00\x00\x00{\"a\":123}\x00"),
+		} {
+			decoded, err := ToUTF8(data)
+			require.NoError(t, err)
+			assert.Equal(t, data, decoded)
+
+			var value JSONMapSlice
+			require.NoError(t, ReadJSON(data, &value))
+			assert.Equal(t, JSONMapSlice{{Key: "a", Value: int64(123)}}, value)
+		}
+	})
+
+	t.Run("should replace invalid characters", func(t *testing.T) {
+		decoded, err := ToUTF8([]byte{0xFE, 0xFF, 0xD8, 0x00, 0x00, 'a'}) // unpaired surrogate
+		require.NoError(t, err)
+		assert.EqualT(t, "�a", string(decoded))
+
+		decoded, err = ToUTF8([]byte{0x00, 0x00, 0xFE, 0xFF, 0x7F, 0xFF, 0xFF, 0xFF})
+		require.NoError(t, err)
+		assert.EqualT(t, "�", string(decoded))
+	})
+
+	t.Run("should fail on truncated documents", func(t *testing.T) {
+		_, err := ToUTF8([]byte{0xFE, 0xFF, 0x00, '{', 0x00})
+		require.ErrorIs(t, err, ErrJSON)
+		assert.StringContainsT(t, err.Error(), "UTF-16BE")
+
+		_, err = ToUTF8([]byte{0xFF, 0xFE, 0x00, 0x00, '{', 0x00})
+		require.ErrorIs(t, err, ErrJSON)
+		assert.StringContainsT(t, err.Error(), "UTF-32LE")
+
+		var value any
+		require.ErrorIs(t, ReadJSON([]byte{0xFF, 0xFE, '{'}, &value), ErrJSON)
+	})
+}
+
+func encodeUTF16(order binary.AppendByteOrder, s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		data = order.AppendUint16(data, unit)
+	}
+
+	return data
+}
+
+func encodeUTF32(order binary.AppendByteOrder, s string) []byte {
+	runes := []rune(s)
+	data := make([]byte, 0, 4*len(runes))
+	for _, r := range runes {
+		data = order.AppendUint32(data, uint32(r))
+	}
+
+	return data
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+type jsonError string
+
+const (
+	// ErrJSON is an error raised by JSON utilities
+	ErrJSON jsonError = "json error"
+)
+
+func (e jsonError) Error() string {
+	return string(e)
+}
@@ -57,14 +57,31 @@ func WriteJSON(value any) ([]byte, error) {
 //
 // NOTE: value must be a pointer.
 //
+// Data in UTF-16 or UTF-32, or with a byte order mark, is converted to UTF-8 first (see [ToUTF8]).
+// Leading and trailing null bytes are ignored.
+//
+// With [WithRelaxed], the data may use the relaxed syntax of JSONC or JSON5 documents:
+// comments, trailing commas, single-quoted strings and unquoted keys (see [RelaxedToJSON]).
+//
 // If the provided value implements [ifaces.SetOrdered], it is a considered an "ordered map" and [ReadJSON]
 // will favor an adapter that supports the [ifaces.OrderedUnmarshal] feature, or fallback to
 // an unordered behavior if none is found.
 //
 // NOTE: to allow types that are [easyjson.Unmarshaler] s to use that route to process JSON,
 // you now need to register the adapter for easyjson at runtime.
-func ReadJSON(data []byte, value any) error {
-	trimmedData := bytes.Trim(data, "\x00")
+func ReadJSON(data []byte, value any, opts ...Option) error {
+	decoded, err := ToUTF8(data)
+	if err != nil {
+		return err
+	}
+
+	trimmedData := bytes.Trim(decoded, "\x00")
+
+	if o := optionsWithDefaults(opts); o.relaxed {
+		if trimmedData, err = RelaxedToJSON(trimmedData); err != nil {
+			return err
+		}
+	}
 
 	if orderedMap, isOrdered := value.(ifaces.SetOrdered); isOrdered {
 		// if the value is an ordered map, favors support for OrderedUnmarshal.
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+// Option configures how [ReadJSON] reads a document.
+type Option func(*options)
+
+type options struct {
+	relaxed bool
+}
+
+// WithRelaxed enables the relaxed JSON syntax of JSONC and JSON5 documents (see [RelaxedToJSON]).
+//
+// This is disabled by default.
+func WithRelaxed(enabled bool) Option {
+	return func(o *options) {
+		o.relaxed = enabled
+	}
+}
+
+func optionsWithDefaults(opts []Option) options {
+	var o options
+
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	return o
+}
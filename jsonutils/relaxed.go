@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// RelaxedToJSON converts a document written with the relaxed syntax of JSONC or JSON5 to strict JSON.
+//
+// The following extensions to the JSON syntax are supported:
+//   - line comments ("// ...") and block comments ("/* ... */"), which are replaced by a space
+//   - a trailing comma after the last member of an object, or the last element of an array
+//   - strings enclosed in single quotes, e.g. 'value'
+//   - line continuations in strings: a backslash followed by a line break, which are both removed
+//   - keys without quotes, e.g. {name: "value"}
+//
+// Other JSON5 extensions, such as hexadecimal numbers or Infinity, are not supported.
+//
+// Keys remain in the same order. A strict JSON document is returned unchanged.
+// The result is not validated: syntax errors are reported when the document is unmarshaled.
+//
+// An error is returned if a string or a block comment is not terminated.
+func RelaxedToJSON(data []byte) ([]byte, error) {
+	r := &relaxer{
+		data: data,
+		out:  make([]byte, 0, len(data)),
+	}
+
+	if err := r.convert(); err != nil {
+		return nil, err
+	}
+
+	return r.out, nil
+}
+
+// relaxer rewrites a relaxed JSON document as strict JSON.
+type relaxer struct {
+	data []byte
+	out  []byte
+
+	pendingComma bool // the last token is a comma, which is removed if followed by '}' or ']'
+	commaPos     int  // the position of the last comma in out
+	isWord       bool // the last token is an unquoted word, which becomes a key if followed by ':'
+	wordStart    int  // the position of the last unquoted word in out
+	wordEnd      int
+}
+
+func (r *relaxer) convert() error {
+	for i := 0; i < len(r.data); {
+		c := r.data[i]
+
+		switch {
+		case c == ' ', c == '\t', c == '\r', c == '\n':
+			r.out = append(r.out, c)
+			i++
+		case c == '/' && i+1 < len(r.data) && r.data[i+1] == '/':
+			end := bytes.IndexByte(r.data[i:], '\n')
+			if end < 0 {
+				end = len(r.data) - i
+			}
+			r.out = append(r.out, ' ')
+			i += end
+		case c == '/' && i+1 < len(r.data) && r.data[i+1] == '*':
+			end := bytes.Index(r.data[i+2:], []byte("*/"))
+			if end < 0 {
+				return fmt.Errorf("unterminated comment at offset %d: %w", i, ErrJSON)
+			}
+			r.out = append(r.out, ' ')
+			i += end + 4 //nolint:mnd // the length of "/*" and "*/"
+		case c == ',':
+			r.token(c)
+			r.pendingComma = true
+			r.commaPos = len(r.out)
+			r.out = append(r.out, c)
+			i++
+		case c == ':':
+			if r.isWord {
+				r.quoteWord()
+			}
+			r.token(c)
+			r.out = append(r.out, c)
+			i++
+		case c == '"':
+			r.token(c)
+			end, err := r.doubleQuoted(i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case c == '\'':
+			r.token(c)
+			end, err := r.singleQuoted(i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case isWordByte(c):
+			r.token(c)
+			end := i
+			for end < len(r.data) && isWordByte(r.data[end]) {
+				end++
+			}
+			r.wordStart = len(r.out)
+			r.out = append(r.out, r.data[i:end]...)
+			r.wordEnd = len(r.out)
+			r.isWord = true
+			i = end
+		default:
+			r.token(c)
+			r.out = append(r.out, c)
+			i++
+		}
+	}
+
+	return nil
+}
+
+// token is called before every token other than white space and comments.
+func (r *relaxer) token(c byte) {
+	if r.pendingComma && (c == '}' || c == ']') {
+		// trailing comma
+		r.out = append(r.out[:r.commaPos], r.out[r.commaPos+1:]...)
+	}
+	r.pendingComma = false
+
+	r.isWord = false
+}
+
+// quoteWord encloses the last unquoted word in double quotes, keeping any white space that follows.
+func (r *relaxer) quoteWord() {
+	word := bytes.Clone(r.out[r.wordStart:r.wordEnd])
+	tail := bytes.Clone(r.out[r.wordEnd:])
+
+	r.out = append(r.out[:r.wordStart], '"')
+	r.out = append(r.out, word...)
+	r.out = append(r.out, '"')
+	r.out = append(r.out, tail...)
+}
+
+// doubleQuoted copies a string in double quotes, starting at position start. It returns the position after the string.
+func (r *relaxer) doubleQuoted(start int) (int, error) {
+	from := start // the start of the part of the string not copied yet
+
+	for i := start + 1; i < len(r.data); i++ {
+		switch r.data[i] {
+		case '\\':
+			if n := r.lineBreak(i + 1); n > 0 {
+				// line continuation
+				r.out = append(r.out, r.data[from:i]...)
+				i += n
+				from = i + 1
+
+				continue
+			}
+
+			i++
+		case '"':
+			r.out = append(r.out, r.data[from:i+1]...)
+
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated string at offset %d: %w", start, ErrJSON)
+}
+
+// singleQuoted rewrites a string in single quotes, starting at position start, as a string in double quotes.
+// It returns the position after the string.
+func (r *relaxer) singleQuoted(start int) (int, error) {
+	r.out = append(r.out, '"')
+
+	for i := start + 1; i < len(r.data); i++ {
+		switch c := r.data[i]; c {
+		case '\\':
+			if i+1 == len(r.data) {
+				return 0, fmt.Errorf("unterminated string at offset %d: %w", start, ErrJSON)
+			}
+
+			i++
+			if n := r.lineBreak(i); n > 0 {
+				// line continuation
+				i += n - 1
+
+				continue
+			}
+
+			if r.data[i] == '\'' {
+				r.out = append(r.out, '\'')
+			} else {
+				r.out = append(r.out, '\\', r.data[i])
+			}
+		case '"':
+			r.out = append(r.out, '\\', '"')
+		case '\'':
+			r.out = append(r.out, '"')
+
+			return i + 1, nil
+		default:
+			r.out = append(r.out, c)
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated string at offset %d: %w", start, ErrJSON)
+}
+
+// lineBreak returns the size of the line break at position i, or 0 if there is none.
+func (r *relaxer) lineBreak(i int) int {
+	switch {
+	case bytes.HasPrefix(r.data[i:], []byte("\r\n")):
+		return 2 //nolint:mnd // the length of "\r\n"
+	case i < len(r.data) && (r.data[i] == '\n' || r.data[i] == '\r'):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isWordByte tells if a byte may be part of an unquoted word: a key, a number or a literal like true.
+//
+// Non-ASCII bytes are accepted, so that keys may contain Unicode letters.
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '$' || c == '-' || c == '+' || c == '.' || c >= 0x80
+}
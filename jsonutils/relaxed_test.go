@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestRelaxedToJSON(t *testing.T) {
+	t.Run("should convert relaxed JSON", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			relaxed  string
+			expected string
+		}{
+			{name: "line comments", relaxed: "{\n// comment\n\"a\": 1 // trailing\n}", expected: `{"a":1}`},
+			{name: "block comments", relaxed: `{/* comment */"a": /* multi
+line */ 1}`, expected: `{"a":1}`},
+			{name: "comment markers in strings", relaxed: `{"url": "http://example.com/*"}`, expected: `{"url":"http://example.com/*"}`},
+			{name: "trailing commas", relaxed: `{"a": [1, 2, ], "b": {"c": true,},}`, expected: `{"a":[1,2],"b":{"c":true}}`},
+			{name: "trailing comma before a comment", relaxed: "[1, // last\n]", expected: `[1]`},
+			{name: "single-quoted strings", relaxed: `{'a': 'it\'s "quoted"'}`, expected: `{"a":"it's \"quoted\""}`},
+			{name: "escapes in single-quoted strings", relaxed: `['tab\thereé\\']`, expected: `["tab\thereé\\"]`},
+			{name: "line continuations", relaxed: "['a long \\\nline', \"another \\\r\nline\", 'old \\\rmac']", expected: `["a long line","another line","old mac"]`},
+			{name: "unquoted keys", relaxed: `{name: "pet", $id: 1, _tag : null, nested: {true: false}}`, expected: `{"name":"pet","$id":1,"_tag":null,"nested":{"true":false}}`},
+			{name: "unquoted keys with a comment before the colon", relaxed: `{name /* key */ : 'pet'}`, expected: `{"name":"pet"}`},
+			{name: "numbers and literals", relaxed: `[-1.5e+3, true, false, null]`, expected: `[-1.5e+3,true,false,null]`},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				data, err := RelaxedToJSON([]byte(tc.relaxed))
+				require.NoError(t, err)
+				assert.JSONEqT(t, tc.expected, string(data))
+			})
+		}
+	})
+
+	t.Run("should leave strict JSON unchanged", func(t *testing.T) {
+		const doc = "{\n  \"a\": [1, \"x\\\"y\", {\"b\": null}],\n  \"c\": \"// not a comment\"\n}"
+
+		data, err := RelaxedToJSON([]byte(doc))
+		require.NoError(t, err)
+		assert.EqualT(t, doc, string(data))
+	})
+
+	t.Run("should fail on unterminated strings and comments", func(t *testing.T) {
+		for _, doc := range []string{`{"a": "b}`, `{'a: 1}`, `['a\`, `{"a": 1 /* comment}`} {
+			_, err := RelaxedToJSON([]byte(doc))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrJSON)
+		}
+	})
+
+	t.Run("should read relaxed JSON with the order of keys preserved", func(t *testing.T) {
+		const doc = `{
+  // a JSONC document
+  zebra: 'last',
+  apple: {b: 2, a: 1,},
+  'mango': [1, 2,],
+}`
+
+		var value JSONMapSlice
+		require.NoError(t, ReadJSON([]byte(doc), &value, WithRelaxed(true)))
+		data, err := WriteJSON(value)
+		require.NoError(t, err)
+		assert.EqualT(t, `{"zebra":"last","apple":{"b":2,"a":1},"mango":[1,2]}`, string(data))
+
+		t.Run("should reject relaxed JSON by default", func(t *testing.T) {
+			var strict JSONMapSlice
+			require.Error(t, ReadJSON([]byte(doc), &strict))
+		})
+	})
+}
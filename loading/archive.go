@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// archiveSeparator separates the path of an archive from the path of an entry in this archive,
+// e.g. "bundle.zip!/specs/pet.yaml".
+const archiveSeparator = "!/"
+
+// splitArchivePath splits a path like "bundle.zip!/specs/pet.yaml" into the path to the archive
+// and the path to the entry in this archive.
+//
+// Only archives with a known extension are considered: ".zip", ".tar", ".tgz", ".tbz2",
+// or ".tar" followed by a compression extension, e.g. ".tar.gz".
+func splitArchivePath(pth string) (archive, entry string, isArchive bool) {
+	archive, entry, found := strings.Cut(pth, archiveSeparator)
+	if !found || !isArchiveName(archive) {
+		return "", "", false
+	}
+
+	return archive, entry, true
+}
+
+func isArchiveName(name string) bool {
+	name = strings.ToLower(stripQuery(name))
+
+	switch path.Ext(name) {
+	case ".zip", ".tar", ".tgz", ".tbz2":
+		return true
+	default:
+		return path.Ext(trimCompressionExt(name)) == ".tar"
+	}
+}
+
+// ArchiveCache retains archives opened by the loader, so that several entries may be loaded from
+// the same archive while fetching or reading it only once.
+//
+// An [ArchiveCache] may be shared by concurrent loads. Archives are kept in memory for the lifetime
+// of the cache.
+//
+// Archives are keyed by canonical path or URL, regardless of the options used to load them:
+// an [ArchiveCache] should only be shared by loads with the same options, e.g. the same [WithFS]
+// or credentials. A [Loader] built with [NewLoader], or a [BatchLoader], has its own [ArchiveCache] by default.
+type ArchiveCache struct {
+	mx       sync.Mutex
+	archives map[string]*archiveCacheEntry
+}
+
+type archiveCacheEntry struct {
+	done chan struct{}
+	fsys fs.FS
+	err  error
+}
+
+// NewArchiveCache builds an empty [ArchiveCache].
+func NewArchiveCache() *ArchiveCache {
+	return &ArchiveCache{
+		archives: make(map[string]*archiveCacheEntry),
+	}
+}
+
+// get retrieves an archive, loading it at most once even when requested concurrently.
+//
+// Failed loads are not retained.
+func (c *ArchiveCache) get(key string, load func() (fs.FS, error)) (fs.FS, error) {
+	c.mx.Lock()
+	entry, found := c.archives[key]
+	if found {
+		c.mx.Unlock()
+		<-entry.done
+
+		return entry.fsys, entry.err
+	}
+
+	entry = &archiveCacheEntry{done: make(chan struct{})}
+	c.archives[key] = entry
+	c.mx.Unlock()
+
+	entry.fsys, entry.err = load()
+	if entry.err != nil {
+		c.mx.Lock()
+		delete(c.archives, key)
+		c.mx.Unlock()
+	}
+	close(entry.done)
+
+	return entry.fsys, entry.err
+}
+
+// OpenArchive loads a zip or tar archive from either a file or a remote url, and exposes its content as a [fs.FS].
+//
+// Compressed tar archives (e.g. ".tar.gz") are decompressed, as described by [WithDecompression].
+//
+// The archive is held in memory. When an [ArchiveCache] is set with [WithArchiveCache],
+// the archive is loaded only once.
+//
+// This is the preferred way to load many entries from an archive with the package functions,
+// e.g. with [WithFS] and the returned [fs.FS]: see also [WithArchiveCache].
+//
+// The maximum size set by [WithMaxBytes] applies to the archive.
+func OpenArchive(ctx context.Context, path string, opts ...Option) (fs.FS, error) {
+	o := optionsWithDefaults(opts)
+
+	return o.openArchive(ctx, path, func(p string) ([]byte, error) {
+		return strategyLoader{options: o}.Load(ctx, p)
+	})
+}
+
+func (o options) openArchive(_ context.Context, archive string, load func(string) ([]byte, error)) (fs.FS, error) {
+	loadFS := func() (fs.FS, error) {
+		data, err := load(archive)
+		if err != nil {
+			return nil, err
+		}
+
+		return archiveFS(data, archive)
+	}
+
+	if o.archiveCache == nil {
+		return loadFS()
+	}
+
+	return o.archiveCache.get(canonicalPath(archive), loadFS)
+}
+
+// withArchiveCache sets a new [ArchiveCache], unless one is already set.
+func (o options) withArchiveCache() options {
+	if o.archiveCache == nil {
+		o.archiveCache = NewArchiveCache()
+	}
+
+	return o
+}
+
+// loadFromArchive loads an entry from an archive, given a path like "bundle.zip!/specs/pet.yaml".
+func (o options) loadFromArchive(ctx context.Context, pth string, load func(string) ([]byte, error)) ([]byte, error) {
+	fsys, entry, err := o.archiveEntry(ctx, pth, load)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fsys.Open(entry)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %q in archive: %w: %w", entry, err, ErrLoader)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return o.readAllDecompressed(file, entry, "")
+}
+
+func (o options) archiveEntry(ctx context.Context, pth string, load func(string) ([]byte, error)) (fs.FS, string, error) {
+	archive, entry, _ := splitArchivePath(pth)
+
+	entry = path.Clean(strings.TrimLeft(entry, "/"))
+	if !fs.ValidPath(entry) || entry == "." {
+		return nil, "", fmt.Errorf("invalid path %q in archive %q: %w", entry, archive, ErrLoader)
+	}
+
+	fsys, err := o.openArchive(ctx, archive, load)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fsys, entry, nil
+}
+
+// archiveFS exposes the content of a zip or tar archive as a [fs.FS].
+func archiveFS(data []byte, name string) (fs.FS, error) {
+	switch {
+	case isZip(data):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid zip archive %q: %w: %w", name, err, ErrLoader)
+		}
+
+		return zr, nil
+	case isTar(data):
+		fsys, err := tarToZip(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tar archive %q: %w: %w", name, err, ErrLoader)
+		}
+
+		return fsys, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive format for %q: expected zip or tar: %w", name, ErrLoader)
+	}
+}
+
+func isZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04")) || bytes.HasPrefix(data, []byte("PK\x05\x06"))
+}
+
+func isTar(data []byte) bool {
+	const (
+		magicOffset = 257
+		magicLen    = 5
+	)
+
+	return len(data) >= magicOffset+magicLen && string(data[magicOffset:magicOffset+magicLen]) == "ustar"
+}
+
+// tarToZip repackages the regular files of a tar archive as an in-memory, uncompressed zip archive,
+// which readily implements [fs.FS].
+func tarToZip(data []byte) (fs.FS, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	tr := tar.NewReader(bytes.NewReader(data))
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     path.Clean(strings.TrimLeft(header.Name, "/")),
+			Method:   zip.Store,
+			Modified: header.ModTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.Copy(w, tr); err != nil { //nolint:gosec // the archive is already entirely in memory
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
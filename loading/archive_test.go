@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestLoadFromArchive(t *testing.T) {
+	entries := map[string][]byte{
+		"specs/pet.yaml":  yamlPetStore,
+		"specs/pet.json":  jsonPetStore,
+		"specs/README.md": []byte("# specs"),
+	}
+	zipped := mustZip(t, entries)
+	tarred := mustTar(t, entries)
+
+	fsys := fstest.MapFS{
+		"bundle.zip":    &fstest.MapFile{Data: zipped},
+		"bundle.tar":    &fstest.MapFile{Data: tarred},
+		"bundle.tar.gz": &fstest.MapFile{Data: mustGzip(t, tarred)},
+		"bundle.tgz":    &fstest.MapFile{Data: mustGzip(t, tarred)},
+		"fake.zip":      &fstest.MapFile{Data: []byte("not an archive")},
+	}
+
+	t.Run("with local archives", func(t *testing.T) {
+		for _, archive := range []string{"bundle.zip", "bundle.tar", "bundle.tar.gz", "bundle.tgz"} {
+			t.Run("should load an entry from "+archive, func(t *testing.T) {
+				b, err := LoadFromFileOrHTTP(archive+"!/specs/pet.yaml", WithFS(fsys))
+				require.NoError(t, err)
+				assert.Equal(t, yamlPetStore, b)
+			})
+		}
+
+		t.Run("should load a YAML document from an archive", func(t *testing.T) {
+			doc, err := YAMLDoc("bundle.zip!/specs/pet.yaml", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should load a JSON document from an archive", func(t *testing.T) {
+			doc, err := JSONDoc("bundle.tar!/specs/pet.json", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should stream an entry from an archive", func(t *testing.T) {
+			rdr, meta, err := Open("bundle.zip!/specs/pet.json", WithFS(fsys))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = rdr.Close()
+			})
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+			assert.EqualT(t, "bundle.zip!/specs/pet.json", meta.URL)
+			assert.EqualT(t, "application/json", meta.ContentType)
+			assert.EqualT(t, int64(len(jsonPetStore)), meta.Size)
+		})
+
+		t.Run("should fail on a missing entry", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("bundle.zip!/specs/missing.yaml", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			require.ErrorIs(t, err, fs.ErrNotExist)
+		})
+
+		t.Run("should fail on an invalid entry path", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("bundle.zip!/../escape.yaml", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "invalid path")
+		})
+
+		t.Run("should fail on an unsupported archive format", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("fake.zip!/specs/pet.yaml", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "unsupported archive format")
+		})
+
+		t.Run("should fail on a missing archive", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("missing.zip!/specs/pet.yaml", WithFS(fsys))
+			require.Error(t, err)
+		})
+
+		t.Run("should not consider a path without an archive extension", func(t *testing.T) {
+			_, _, isArchive := splitArchivePath("folder!/specs/pet.yaml")
+			assert.FalseT(t, isArchive)
+		})
+	})
+
+	t.Run("with remote archives", func(t *testing.T) {
+		var requests atomic.Int32
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write(zipped)
+		}))
+		t.Cleanup(serv.Close)
+
+		t.Run("should fetch the archive only once with an archive cache", func(t *testing.T) {
+			requests.Store(0)
+			cache := NewArchiveCache()
+
+			b, err := LoadFromFileOrHTTP(serv.URL+"/bundle.zip!/specs/pet.yaml", WithArchiveCache(cache))
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+
+			b, err = LoadFromFileOrHTTP(serv.URL+"/bundle.zip!/specs/pet.json", WithArchiveCache(cache))
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+
+			assert.EqualT(t, int32(1), requests.Load())
+		})
+
+		t.Run("should fetch the archive only once with a Loader", func(t *testing.T) {
+			requests.Store(0)
+			loader := NewLoader()
+
+			b, err := loader.Load(context.Background(), serv.URL+"/bundle.zip!/specs/pet.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+
+			b, err = loader.Load(context.Background(), strings.ToUpper(serv.URL[:4])+serv.URL[4:]+"/bundle.zip!/specs/pet.json")
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+
+			assert.EqualT(t, int32(1), requests.Load())
+		})
+
+		t.Run("should fetch the archive only once with a BatchLoader", func(t *testing.T) {
+			requests.Store(0)
+
+			docs, err := NewBatchLoader().LoadMany(context.Background(), []string{
+				serv.URL + "/bundle.zip!/specs/pet.yaml",
+				serv.URL + "/bundle.zip!/specs/pet.json",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, [][]byte{yamlPetStore, jsonPetStore}, docs)
+
+			assert.EqualT(t, int32(1), requests.Load())
+		})
+
+		t.Run("should fetch the archive on every load without an archive cache", func(t *testing.T) {
+			requests.Store(0)
+
+			for range 2 {
+				_, err := LoadFromFileOrHTTP(serv.URL + "/bundle.zip!/specs/pet.yaml")
+				require.NoError(t, err)
+			}
+
+			assert.EqualT(t, int32(2), requests.Load())
+		})
+	})
+
+	t.Run("with OpenArchive", func(t *testing.T) {
+		t.Run("should expose a tar archive as a fs.FS", func(t *testing.T) {
+			archive, err := OpenArchive(context.Background(), "bundle.tar.gz", WithFS(fsys))
+			require.NoError(t, err)
+
+			b, err := fs.ReadFile(archive, "specs/pet.json")
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+
+			matches, err := fs.Glob(archive, "specs/*.md")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"specs/README.md"}, matches)
+		})
+
+		t.Run("should apply the maximum size to the archive", func(t *testing.T) {
+			_, err := OpenArchive(context.Background(), "bundle.zip", WithFS(fsys), WithMaxBytes(16))
+			require.Error(t, err)
+
+			var maxBytesErr *MaxBytesError
+			require.ErrorAs(t, err, &maxBytesErr)
+		})
+	})
+}
+
+func mustZip(t *testing.T, entries map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func mustTar(t *testing.T, entries map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "specs/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
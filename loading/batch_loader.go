@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// defaultConcurrency is the default number of documents loaded concurrently by a [BatchLoader].
+const defaultConcurrency = 8
+
+// BatchLoader loads documents from files or remote urls, like [LoadFromFileOrHTTP] does,
+// and may be reused across calls.
+//
+// A [BatchLoader] is intended for loading many documents, e.g. the targets of "$ref" in a specification:
+//   - the number of documents loaded concurrently is bounded (see [WithConcurrency])
+//   - concurrent requests for the same document are collapsed into a single load
+//   - connections to remote hosts are reused, and limited per host (see [WithMaxConnsPerHost])
+//   - archives are fetched or read only once (see [WithArchiveCache])
+//
+// A [BatchLoader] is safe for concurrent use.
+type BatchLoader struct {
+	options options
+	slots   chan struct{}
+
+	mx      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is an ongoing load, shared by concurrent requests for the same document.
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// NewBatchLoader builds a [BatchLoader] with some options, which apply to all loads.
+func NewBatchLoader(opts ...Option) *BatchLoader {
+	o := optionsWithDefaults(opts).withArchiveCache()
+
+	if o.maxConnsPerHost > 0 {
+		o.client = clientWithMaxConnsPerHost(o.client, o.maxConnsPerHost)
+		o = o.withGuardedClient()
+	}
+
+	return &BatchLoader{
+		options: o,
+		slots:   make(chan struct{}, o.concurrency),
+		flights: make(map[string]*flight),
+	}
+}
+
+// Load loads a document from either a file or a remote url.
+//
+// Concurrent loads of the same document, designated by the same canonical path or URL,
+// are carried out only once.
+func (l *BatchLoader) Load(ctx context.Context, path string) ([]byte, error) {
+	return observeLoad(ctx, l.options, path, func() ([]byte, error) {
+		return l.loadVerified(ctx, path)
+	})
+}
+
+// loadVerified loads a document, and verifies its integrity.
+func (l *BatchLoader) loadVerified(ctx context.Context, path string) ([]byte, error) {
+	path, expected := l.options.splitIntegrity(path)
+
+	data, err := l.loadShared(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = l.options.verifyIntegrity(path, data, expected); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// loadShared loads a document, or waits for an ongoing load of the same document.
+func (l *BatchLoader) loadShared(ctx context.Context, path string) ([]byte, error) {
+	key := canonicalPath(path)
+
+	for {
+		l.mx.Lock()
+		current, found := l.flights[key]
+		if !found {
+			current = &flight{done: make(chan struct{})}
+			l.flights[key] = current
+			l.mx.Unlock()
+
+			data, err := l.load(ctx, path)
+			if err == nil {
+				// concurrent callers get their own copy of the document
+				current.data = bytes.Clone(data)
+			}
+			current.err = err
+
+			l.mx.Lock()
+			delete(l.flights, key)
+			l.mx.Unlock()
+			close(current.done)
+
+			return data, err
+		}
+		l.mx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-current.done:
+		}
+
+		if isContextError(current.err) && ctx.Err() == nil {
+			// the load was cancelled by the context of another caller: try again
+			continue
+		}
+
+		if current.err != nil {
+			return nil, current.err
+		}
+
+		return bytes.Clone(current.data), nil
+	}
+}
+
+// LoadMany loads several documents concurrently.
+//
+// The returned documents are in the same order as the paths. Whenever some documents fail to load,
+// the corresponding documents are nil, and the returned error joins all errors.
+func (l *BatchLoader) LoadMany(ctx context.Context, paths []string) ([][]byte, error) {
+	docs := make([][]byte, len(paths))
+	errs := make([]error, len(paths))
+
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			docs[i], errs[i] = l.Load(ctx, path)
+		}()
+	}
+	wg.Wait()
+
+	return docs, errors.Join(errs...)
+}
+
+// load carries out a single load, bounded by the concurrency limit.
+func (l *BatchLoader) load(ctx context.Context, path string) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l.slots <- struct{}{}:
+	}
+	defer func() {
+		<-l.slots
+	}()
+
+	return strategyLoader{options: l.options}.load(ctx, path)
+}
+
+// canonicalPath normalizes a path or URL, so that the same document is designated by the same key.
+//
+// For remote documents, the scheme and host are lower-cased, default ports and fragments are removed.
+// Local paths are made absolute.
+func canonicalPath(path string) string {
+	scheme, hasScheme := uriScheme(path)
+	if !hasScheme {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+
+		return path
+	}
+
+	if scheme != schemeHTTP && scheme != schemeHTTPS {
+		return path
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+
+	u.Scheme = scheme
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if port := u.Port(); scheme == schemeHTTP && port == "80" || scheme == schemeHTTPS && port == "443" {
+		u.Host = u.Hostname()
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return u.String()
+}
+
+// clientWithMaxConnsPerHost returns a copy of an [http.Client] with a limit of connections per host.
+//
+// The limit applies only to clients using an [http.Transport]. Other clients are left unchanged.
+func clientWithMaxConnsPerHost(client *http.Client, n int) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	limited := base.Clone()
+	limited.MaxConnsPerHost = n
+	limited.MaxIdleConnsPerHost = n
+
+	clone := *client
+	clone.Transport = limited
+
+	return &clone
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
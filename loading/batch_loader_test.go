@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestBatchLoader(t *testing.T) {
+	t.Run("should collapse concurrent loads of the same document", func(t *testing.T) {
+		var requests atomic.Int32
+		release := make(chan struct{})
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			serveJSONPetStore(rw, r)
+		}))
+		t.Cleanup(serv.Close)
+
+		loader := NewBatchLoader()
+		paths := []string{
+			serv.URL + "/pet.json",
+			serv.URL + "/pet.json#/definitions/Pet",
+			serv.URL + "/pet.json",
+		}
+
+		done := make(chan struct{})
+		var (
+			docs [][]byte
+			err  error
+		)
+		go func() {
+			defer close(done)
+			docs, err = loader.LoadMany(context.Background(), paths)
+		}()
+
+		require.Eventually(t, func() bool { return requests.Load() > 0 }, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond) // let the other loads join
+		close(release)
+		<-done
+
+		require.NoError(t, err)
+		require.Len(t, docs, len(paths))
+		for _, doc := range docs {
+			assert.Equal(t, jsonPetStore, doc)
+		}
+		assert.EqualT(t, int32(1), requests.Load())
+
+		docs[0][0] = 'x'
+		assert.Equal(t, jsonPetStore, docs[1])
+	})
+
+	t.Run("should bound the number of concurrent loads", func(t *testing.T) {
+		var current, highest atomic.Int32
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				h := highest.Load()
+				if n <= h || highest.CompareAndSwap(h, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			serveOK(rw, r)
+		}))
+		t.Cleanup(serv.Close)
+
+		loader := NewBatchLoader(WithConcurrency(2), WithMaxConnsPerHost(4))
+		paths := make([]string, 0, 10)
+		for i := range 10 {
+			paths = append(paths, serv.URL+"/doc/"+string(rune('a'+i)))
+		}
+
+		docs, err := loader.LoadMany(context.Background(), paths)
+		require.NoError(t, err)
+		require.Len(t, docs, len(paths))
+		assert.LessOrEqualT(t, highest.Load(), int32(2))
+	})
+
+	t.Run("should report all errors", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/ok" {
+				serveOK(rw, r)
+
+				return
+			}
+			serveKO(rw, r)
+		}))
+		t.Cleanup(serv.Close)
+
+		docs, err := NewBatchLoader().LoadMany(context.Background(), []string{
+			serv.URL + "/ko",
+			serv.URL + "/ok",
+			"fixtures/missing.yaml",
+		})
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.StringContainsT(t, err.Error(), "/ko")
+		assert.StringContainsT(t, err.Error(), "missing.yaml")
+
+		require.Len(t, docs, 3)
+		assert.Nil(t, docs[0])
+		assert.Equal(t, []byte("the content"), docs[1])
+		assert.Nil(t, docs[2])
+	})
+
+	t.Run("should load local documents", func(t *testing.T) {
+		doc, err := NewBatchLoader(WithFS(embeddedFixtures)).Load(context.Background(), "fixtures/petstore_fixture.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, yamlPetStore, doc)
+	})
+
+	t.Run("should not wait for another load once cancelled", func(t *testing.T) {
+		release := make(chan struct{})
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			<-release
+			serveOK(rw, r)
+		}))
+		t.Cleanup(serv.Close)
+
+		loader := NewBatchLoader()
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = loader.Load(context.Background(), serv.URL+"/doc")
+		}()
+		t.Cleanup(wg.Wait)
+		t.Cleanup(func() { close(release) })
+
+		require.Eventually(t, func() bool {
+			loader.mx.Lock()
+			defer loader.mx.Unlock()
+
+			return len(loader.flights) == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := loader.Load(ctx, serv.URL+"/doc")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestCanonicalPath(t *testing.T) {
+	abs, err := filepath.Abs("fixtures/pet.yaml")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		path, expected string
+	}{
+		{"HTTPS://Example.COM:443/specs/pet.yaml#/definitions", "https://example.com/specs/pet.yaml"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/pet.yaml?v=1", "http://example.com:8080/pet.yaml?v=1"},
+		{"fixtures/pet.yaml", abs},
+		{"data:,hello", "data:,hello"},
+	} {
+		assert.EqualT(t, tc.expected, canonicalPath(tc.path), tc.path)
+	}
+}
+
+func TestClientWithMaxConnsPerHost(t *testing.T) {
+	client := clientWithMaxConnsPerHost(http.DefaultClient, 3)
+	require.NotSame(t, http.DefaultClient, client)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.TrueT(t, ok)
+	assert.EqualT(t, 3, transport.MaxConnsPerHost)
+
+	custom := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+	assert.Same(t, custom, clientWithMaxConnsPerHost(custom, 3))
+}
@@ -0,0 +1,410 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is a storage backend for remote documents.
+//
+// Entries are keyed by URL, and by the "Accept" header sent to the server, if any: the one negotiated by [Doc],
+// or set with [WithCustomHeaders].
+//
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get retrieves a cached entry. It returns false if there is no such entry.
+	Get(key string) (CacheEntry, bool)
+
+	// Put stores an entry in the cache.
+	Put(key string, entry CacheEntry) error
+
+	// Delete removes an entry from the cache. Deleting a missing entry is not an error.
+	Delete(key string) error
+}
+
+// CacheEntry is a remote document stored in a [Cache], along with its HTTP validators.
+type CacheEntry struct {
+	// Body is the content of the document.
+	Body []byte `json:"-"`
+
+	// ContentType is the media type of the document returned by the server, if any.
+	ContentType string `json:"contentType,omitempty"`
+
+	// ETag is the entity tag returned by the server, if any.
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified is the last modification date returned by the server, if any.
+	LastModified string `json:"lastModified,omitempty"`
+
+	// Expires is the time until which the entry may be served without revalidation.
+	//
+	// It is derived from the "Cache-Control: max-age" directive returned by the server.
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+// IsFresh indicates if the entry may be served at time now without revalidation.
+func (e CacheEntry) IsFresh(now time.Time) bool {
+	return !e.Expires.IsZero() && now.Before(e.Expires)
+}
+
+// result builds the outcome of a remote fetch served from the cache.
+func (e CacheEntry) result(url string) httpResult {
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+
+	if e.LastModified != "" {
+		header.Set("Last-Modified", e.LastModified)
+	}
+
+	return httpResult{data: e.Body, header: header, url: url}
+}
+
+func (e CacheEntry) setConditionalHeaders(header http.Header) {
+	if e.ETag != "" {
+		header.Set("If-None-Match", e.ETag)
+	}
+
+	if e.LastModified != "" {
+		header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// cacheControl holds the directives from a "Cache-Control" response header relevant to the loader.
+type cacheControl struct {
+	noStore   bool
+	noCache   bool
+	maxAge    time.Duration
+	hasMaxAge bool
+}
+
+func parseCacheControl(header http.Header) cacheControl {
+	var cc cacheControl
+
+	for directive := range strings.SplitSeq(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil || seconds < 0 {
+				continue
+			}
+			cc.maxAge = time.Duration(seconds) * time.Second
+			cc.hasMaxAge = true
+		}
+	}
+
+	return cc
+}
+
+// expires computes the expiry of an entry fetched at time now.
+func (cc cacheControl) expires(now time.Time) time.Time {
+	if cc.noCache || !cc.hasMaxAge {
+		return time.Time{}
+	}
+
+	return now.Add(cc.maxAge)
+}
+
+// loadCachedHTTP loads a remote document using a cache.
+//
+// Fresh entries are served directly. Stale entries are revalidated with a conditional request.
+//
+// In cache-only mode, the remote server is never contacted.
+func loadCachedHTTP(ctx context.Context, path string, o options) (httpResult, error) {
+	if o.cache == nil {
+		return httpResult{}, fmt.Errorf("document at %q is not available: cache-only mode requires a cache: %w", path, ErrLoader)
+	}
+
+	key := o.cacheKey(path)
+	entry, found := o.cache.Get(key)
+
+	if o.cacheOnly {
+		if !found {
+			return httpResult{}, fmt.Errorf("document at %q is not available from the cache (cache-only mode): %w", path, ErrLoader)
+		}
+
+		return o.cacheHit(ctx, path, entry, 0)
+	}
+
+	now := time.Now()
+	if found && entry.IsFresh(now) {
+		return o.cacheHit(ctx, path, entry, 0)
+	}
+
+	var cached *CacheEntry
+	if found {
+		cached = &entry
+	}
+
+	result, err := fetchHTTPWithRetry(ctx, path, o, cached)
+	if err != nil {
+		return httpResult{}, err
+	}
+
+	cc := parseCacheControl(result.header)
+
+	if result.notModified {
+		entry.Expires = cc.expires(now)
+		if etag := result.header.Get("ETag"); etag != "" {
+			entry.ETag = etag
+		}
+		_ = o.cache.Put(key, entry) // caching is best effort
+
+		return o.cacheHit(ctx, path, entry, http.StatusNotModified)
+	}
+
+	if cc.noStore {
+		if found {
+			_ = o.cache.Delete(key) // the stale entry must not be served again
+		}
+
+		return result, nil
+	}
+
+	_ = o.cache.Put(key, CacheEntry{ // caching is best effort
+		Body:         bytes.Clone(result.data),
+		ContentType:  result.header.Get("Content-Type"),
+		ETag:         result.header.Get("ETag"),
+		LastModified: result.header.Get("Last-Modified"),
+		Expires:      cc.expires(now),
+	})
+
+	return result, nil
+}
+
+// cacheKey is the key of a remote document in the cache: its URL, and the "Accept" header sent
+// to the server, if any, since the server may respond with a different representation.
+func (o options) cacheKey(path string) string {
+	accept := o.acceptHeader(path)
+	if accept == "" {
+		return path
+	}
+
+	return path + " (Accept: " + accept + ")"
+}
+
+// acceptHeader is the "Accept" header sent to fetch a remote document: the one negotiated by [Doc],
+// unless overridden by [WithCustomHeaders] or [WithHostCustomHeaders].
+func (o options) acceptHeader(path string) string {
+	accept := o.accept
+
+	for key, value := range o.customHeaders {
+		if http.CanonicalHeaderKey(key) == "Accept" {
+			accept = value
+		}
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return accept
+	}
+
+	for _, scoped := range o.hostHeaders {
+		if !matchHost(scoped.pattern, u) {
+			continue
+		}
+
+		for key, value := range scoped.headers {
+			if http.CanonicalHeaderKey(key) == "Accept" {
+				accept = value
+			}
+		}
+	}
+
+	return accept
+}
+
+// cacheHit serves a document from the cache, within the limit set by [WithMaxBytes].
+func (o options) cacheHit(ctx context.Context, path string, entry CacheEntry, statusCode int) (httpResult, error) {
+	if _, err := checkMaxBytes(entry.Body, path, o.maxBytes); err != nil {
+		return httpResult{}, err
+	}
+
+	o.notify(ctx, onCacheHit, Event{
+		Path:       path,
+		URL:        path,
+		StatusCode: statusCode,
+		Bytes:      int64(len(entry.Body)),
+	})
+
+	entry.Body = bytes.Clone(entry.Body) // the caller may modify the document
+
+	return entry.result(path), nil
+}
+
+var _ Cache = &MemoryCache{}
+
+// MemoryCache is an in-memory [Cache].
+type MemoryCache struct {
+	mx      sync.RWMutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache builds an empty in-memory [Cache].
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]CacheEntry),
+	}
+}
+
+// Get retrieves a cached entry.
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	entry, ok := c.entries[key]
+
+	return entry, ok
+}
+
+// Put stores an entry in the cache.
+func (c *MemoryCache) Put(key string, entry CacheEntry) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.entries[key] = entry
+
+	return nil
+}
+
+// Delete removes an entry from the cache.
+func (c *MemoryCache) Delete(key string) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	delete(c.entries, key)
+
+	return nil
+}
+
+var _ Cache = &DirCache{}
+
+// DirCache is a [Cache] that stores entries as files in a directory.
+//
+// Every entry is stored as a pair of files: one with the body of the document,
+// and one with its metadata (validators and expiry).
+//
+// A [DirCache] may be shared by several processes: files are updated atomically.
+type DirCache struct {
+	dir string
+}
+
+// NewDirCache builds a [Cache] persisted in a directory.
+//
+// The directory is created when the first entry is stored.
+func NewDirCache(dir string) *DirCache {
+	return &DirCache{dir: dir}
+}
+
+// Get retrieves a cached entry.
+//
+// Entries that cannot be read are reported as not found.
+func (c *DirCache) Get(key string) (CacheEntry, bool) {
+	base := c.baseName(key)
+
+	meta, err := os.ReadFile(base + ".meta.json")
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	var entry CacheEntry
+	if err = json.Unmarshal(meta, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+
+	entry.Body, err = os.ReadFile(base + ".body")
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Put stores an entry in the cache.
+func (c *DirCache) Put(key string, entry CacheEntry) error {
+	const dirPerm = 0o750
+
+	if err := os.MkdirAll(c.dir, dirPerm); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	base := c.baseName(key)
+
+	// the body is written first: metadata without a body are never observed
+	if err := writeFileAtomic(base+".body", entry.Body); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(base+".meta.json", meta)
+}
+
+// Delete removes an entry from the cache.
+func (c *DirCache) Delete(key string) error {
+	base := c.baseName(key)
+
+	// the metadata are removed first: a body without metadata is never served
+	for _, name := range []string{base + ".meta.json", base + ".body"} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *DirCache) baseName(key string) string {
+	h := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.dir, hex.EncodeToString(h[:]))
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	return nil
+}
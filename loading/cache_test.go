@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestWithCache(t *testing.T) {
+	for _, backend := range []struct {
+		Name     string
+		NewCache func(*testing.T) Cache
+	}{
+		{Name: "memory", NewCache: func(*testing.T) Cache { return NewMemoryCache() }},
+		{Name: "directory", NewCache: func(t *testing.T) Cache { return NewDirCache(t.TempDir()) }},
+	} {
+		t.Run("with "+backend.Name+" cache", func(t *testing.T) {
+			t.Run("should revalidate with ETag", func(t *testing.T) {
+				ts, stats := serveCacheable(`"v1"`, "", "")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+
+				for range 3 {
+					b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+					require.NoError(t, err)
+					assert.EqualT(t, "the content", string(b))
+				}
+
+				assert.EqualT(t, int32(3), stats.requests.Load())
+				assert.EqualT(t, int32(2), stats.notModified.Load())
+			})
+
+			t.Run("should revalidate with Last-Modified", func(t *testing.T) {
+				ts, stats := serveCacheable("", "Wed, 01 Oct 2025 12:00:00 GMT", "")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+
+				for range 2 {
+					b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+					require.NoError(t, err)
+					assert.EqualT(t, "the content", string(b))
+				}
+
+				assert.EqualT(t, int32(2), stats.requests.Load())
+				assert.EqualT(t, int32(1), stats.notModified.Load())
+			})
+
+			t.Run("should serve fresh entries without revalidation", func(t *testing.T) {
+				ts, stats := serveCacheable(`"v1"`, "", "max-age=3600")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+
+				for range 3 {
+					b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+					require.NoError(t, err)
+					assert.EqualT(t, "the content", string(b))
+				}
+
+				assert.EqualT(t, int32(1), stats.requests.Load())
+			})
+
+			t.Run("should not store with no-store", func(t *testing.T) {
+				ts, stats := serveCacheable(`"v1"`, "", "no-store")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+
+				for range 2 {
+					_, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+					require.NoError(t, err)
+				}
+
+				assert.EqualT(t, int32(2), stats.requests.Load())
+				assert.EqualT(t, int32(0), stats.notModified.Load())
+				_, found := cache.Get(ts.URL)
+				assert.FalseT(t, found)
+			})
+
+			t.Run("should delete a stale entry with no-store", func(t *testing.T) {
+				ts, _ := serveCacheable(`"v1"`, "", "no-store")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+				require.NoError(t, cache.Put(ts.URL, CacheEntry{Body: []byte("stale content"), ETag: `"v0"`}))
+
+				b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+				require.NoError(t, err)
+				assert.EqualT(t, "the content", string(b))
+
+				_, found := cache.Get(ts.URL)
+				assert.FalseT(t, found)
+			})
+
+			t.Run("should not share cached documents with callers", func(t *testing.T) {
+				ts, _ := serveCacheable(`"v1"`, "", "max-age=3600")
+				defer ts.Close()
+				cache := backend.NewCache(t)
+
+				for range 3 {
+					b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+					require.NoError(t, err)
+					assert.EqualT(t, "the content", string(b))
+					b[2] = 'X'
+				}
+			})
+
+			t.Run("should serve from cache only", func(t *testing.T) {
+				ts, stats := serveCacheable(`"v1"`, "", "")
+				cache := backend.NewCache(t)
+
+				_, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+				require.NoError(t, err)
+				ts.Close()
+
+				b, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache), WithCacheOnly(true))
+				require.NoError(t, err)
+				assert.EqualT(t, "the content", string(b))
+				assert.EqualT(t, int32(1), stats.requests.Load())
+
+				t.Run("should fail on cache miss", func(t *testing.T) {
+					_, err := LoadFromFileOrHTTP(ts.URL+"/other", WithCache(cache), WithCacheOnly(true))
+					require.Error(t, err)
+					require.ErrorIs(t, err, ErrLoader)
+				})
+			})
+		})
+	}
+
+	t.Run("should key entries by the Accept header sent", func(t *testing.T) {
+		// the server serves YAML when asked for it, and JSON otherwise
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				rw.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			if r.Header.Get("Accept") == "application/yaml" {
+				serveYAMLPetStore(rw, r)
+
+				return
+			}
+
+			serveJSONPetStore(rw, r)
+		}))
+		defer ts.Close()
+		cache := NewMemoryCache()
+
+		for _, tc := range []struct {
+			opts     []Option
+			expected []byte
+		}{
+			{opts: []Option{WithCustomHeaders(map[string]string{"accept": "application/yaml"})}, expected: yamlPetStore},
+			{expected: jsonPetStore},
+			{opts: []Option{WithHostCustomHeaders("127.0.0.1", map[string]string{"Accept": "application/yaml"})}, expected: yamlPetStore},
+		} {
+			b, err := LoadFromFileOrHTTP(ts.URL, append(tc.opts, WithCache(cache))...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, b)
+		}
+	})
+
+	t.Run("should fail in cache-only mode without a cache", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(serveOK))
+		defer ts.Close()
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithCacheOnly(true))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should apply the max bytes limit to cached documents", func(t *testing.T) {
+		for _, tc := range []struct {
+			name         string
+			cacheControl string
+			opts         []Option
+		}{
+			{name: "revalidated", cacheControl: ""},
+			{name: "fresh", cacheControl: "max-age=3600"},
+			{name: "cache-only", cacheControl: "", opts: []Option{WithCacheOnly(true)}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ts, _ := serveCacheable(`"v1"`, "", tc.cacheControl)
+				defer ts.Close()
+				cache := NewMemoryCache()
+
+				_, err := LoadFromFileOrHTTP(ts.URL, WithCache(cache))
+				require.NoError(t, err)
+
+				_, err = LoadFromFileOrHTTP(ts.URL, append(tc.opts, WithCache(cache), WithMaxBytes(5))...)
+				require.Error(t, err)
+				var maxBytesErr *MaxBytesError
+				require.ErrorAs(t, err, &maxBytesErr)
+			})
+		}
+	})
+
+	t.Run("should persist entries across directory caches", func(t *testing.T) {
+		ts, stats := serveCacheable(`"v1"`, "", "")
+		defer ts.Close()
+		dir := t.TempDir()
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithCache(NewDirCache(dir)))
+		require.NoError(t, err)
+
+		b, err := LoadFromFileOrHTTP(ts.URL, WithCache(NewDirCache(dir)))
+		require.NoError(t, err)
+		assert.EqualT(t, "the content", string(b))
+		assert.EqualT(t, int32(1), stats.notModified.Load())
+	})
+}
+
+func TestParseCacheControl(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should parse max-age", func(t *testing.T) {
+		cc := parseCacheControl(http.Header{"Cache-Control": []string{"public, max-age=60"}})
+		assert.EqualT(t, now.Add(time.Minute), cc.expires(now))
+	})
+
+	t.Run("should not expire with no-cache", func(t *testing.T) {
+		cc := parseCacheControl(http.Header{"Cache-Control": []string{"max-age=60, no-cache"}})
+		assert.TrueT(t, cc.expires(now).IsZero())
+	})
+
+	t.Run("should ignore invalid max-age", func(t *testing.T) {
+		cc := parseCacheControl(http.Header{"Cache-Control": []string{"max-age=soon"}})
+		assert.TrueT(t, cc.expires(now).IsZero())
+	})
+
+	t.Run("should detect no-store", func(t *testing.T) {
+		cc := parseCacheControl(http.Header{"Cache-Control": []string{"No-Store"}})
+		assert.TrueT(t, cc.noStore)
+	})
+}
+
+type cacheStats struct {
+	requests    atomic.Int32
+	notModified atomic.Int32
+}
+
+// serveCacheable serves some content with validators, and honors conditional requests.
+func serveCacheable(etag, lastModified, cacheControl string) (*httptest.Server, *cacheStats) {
+	stats := new(cacheStats)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		stats.requests.Add(1)
+
+		if etag != "" {
+			rw.Header().Set("ETag", etag)
+		}
+		if lastModified != "" {
+			rw.Header().Set("Last-Modified", lastModified)
+		}
+		if cacheControl != "" {
+			rw.Header().Set("Cache-Control", cacheControl)
+		}
+
+		if (etag != "" && r.Header.Get("If-None-Match") == etag) ||
+			(lastModified != "" && r.Header.Get("If-Modified-Since") == lastModified) {
+			stats.notModified.Add(1)
+			rw.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		serveOK(rw, r)
+	}))
+
+	return ts, stats
+}
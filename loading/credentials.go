@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CredentialsProvider supplies the credentials to access a remote document, as HTTP headers
+// such as "Authorization".
+//
+// The provider is called for every request, with the URL of the requested document. This allows for
+// credentials that depend on the host, or that expire (e.g. OAuth tokens).
+//
+// When the server responds with the status 401 "Unauthorized", the provider is called once more,
+// with a context for which [IsCredentialsRefresh] is true, and the request is sent again.
+//
+// Credentials are never sent after a redirect to another host, or to another scheme.
+type CredentialsProvider func(ctx context.Context, u *url.URL) (http.Header, error)
+
+type credentialsRefreshKey struct{}
+
+// IsCredentialsRefresh tells a [CredentialsProvider] that the previous credentials were rejected
+// by the server with the status 401 "Unauthorized", e.g. because a token has expired.
+//
+// The provider should then refresh its credentials rather than serve them from some cache.
+func IsCredentialsRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(credentialsRefreshKey{}).(bool)
+
+	return refresh
+}
+
+// requestCredentials lists the headers set on a request to convey credentials.
+type requestCredentials struct {
+	// global headers apply to all hosts. They are removed after a redirect to another host.
+	global []string
+
+	// scoped headers apply to the hosts matching a pattern. They are evaluated again after a redirect to another host.
+	scoped []string
+}
+
+// setCredentials sets the credentials to access a remote document on a request.
+//
+// Credentials scoped to the host of the request take precedence over credentials for all hosts.
+func (o options) setCredentials(req *http.Request, refresh bool) (requestCredentials, error) {
+	var creds requestCredentials
+
+	if _, err := o.netrcMachines(); err != nil {
+		return creds, err
+	}
+
+	scoped, matched, err := o.setHostCredentials(req, refresh)
+	if err != nil {
+		return creds, err
+	}
+	creds.scoped = scoped
+
+	if matched {
+		return creds, nil
+	}
+
+	creds.global, err = o.setGlobalCredentials(req, refresh)
+
+	return creds, err
+}
+
+// setHostCredentials sets the headers and credentials scoped to the host of a request.
+//
+// It reports whether some credentials matched this host.
+func (o options) setHostCredentials(req *http.Request, refresh bool) ([]string, bool, error) {
+	var keys []string
+
+	for _, scoped := range o.hostHeaders {
+		if !matchHost(scoped.pattern, req.URL) {
+			continue
+		}
+
+		for key, value := range scoped.headers {
+			req.Header.Set(key, value)
+			keys = append(keys, key)
+		}
+	}
+
+	provider := o.hostProvider(req.URL)
+	if provider == nil {
+		return keys, false, nil
+	}
+
+	providerKeys, err := setProviderCredentials(req, provider, refresh)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return append(keys, providerKeys...), true, nil
+}
+
+// netrcMachines returns the entries of the .netrc file set with [WithNetrc], if any.
+func (o options) netrcMachines() ([]netrcMachine, error) {
+	if o.netrc == nil {
+		return nil, nil
+	}
+
+	return o.netrc()
+}
+
+// hostProvider yields the credentials scoped to a host, if any.
+func (o options) hostProvider(u *url.URL) CredentialsProvider {
+	for _, scoped := range o.hostCredentials {
+		if matchHost(scoped.pattern, u) {
+			return scoped.provider
+		}
+	}
+
+	machines, _ := o.netrcMachines() // errors are reported by setCredentials
+	if machine, ok := findNetrcMachine(machines, u.Hostname()); ok {
+		return basicAuthProvider(machine.login, machine.password)
+	}
+
+	return nil
+}
+
+// setGlobalCredentials sets the credentials that apply to all hosts.
+func (o options) setGlobalCredentials(req *http.Request, refresh bool) ([]string, error) {
+	var keys []string
+
+	if o.basicAuthUsername != "" && o.basicAuthPassword != "" {
+		req.SetBasicAuth(o.basicAuthUsername, o.basicAuthPassword)
+		keys = append(keys, "Authorization")
+	}
+
+	if o.credentials == nil {
+		machines, _ := o.netrcMachines() // errors are reported by setCredentials
+		if machine, ok := findNetrcMachine(machines, ""); ok && len(keys) == 0 {
+			req.SetBasicAuth(machine.login, machine.password)
+			keys = append(keys, "Authorization")
+		}
+
+		return keys, nil
+	}
+
+	providerKeys, err := setProviderCredentials(req, o.credentials, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(keys, providerKeys...), nil
+}
+
+// refreshesCredentials tells if credentials may be refreshed after the status 401 "Unauthorized".
+func (o options) refreshesCredentials() bool {
+	return o.credentials != nil || len(o.hostCredentials) > 0
+}
+
+func setProviderCredentials(req *http.Request, provider CredentialsProvider, refresh bool) ([]string, error) {
+	ctx := req.Context()
+	if refresh {
+		ctx = context.WithValue(ctx, credentialsRefreshKey{}, true)
+	}
+
+	header, err := provider(ctx, req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("could not get credentials for %q: %w: %w", req.URL.Redacted(), err, ErrLoader)
+	}
+
+	keys := make([]string, 0, len(header))
+	for key, values := range header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func basicAuthProvider(username, password string) CredentialsProvider {
+	return func(context.Context, *url.URL) (http.Header, error) {
+		token := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+
+		return http.Header{"Authorization": []string{"Basic " + token}}, nil
+	}
+}
+
+// initialRequest walks back the chain of redirects that led to a request.
+func initialRequest(req *http.Request) *http.Request {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+
+	return req
+}
+
+func sameHost(u, v *url.URL) bool {
+	return u.Scheme == v.Scheme && strings.EqualFold(u.Host, v.Host)
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestCredentials(t *testing.T) {
+	t.Run("should send a bearer token", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveBearerTokenFunc("secret")))
+		t.Cleanup(serv.Close)
+
+		b, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithBearerToken("secret"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("the content"), b)
+
+		_, err = LoadFromFileOrHTTP(serv.URL+"/doc", WithBearerToken("wrong"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should call the provider for every request", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveRequireHeaderFunc("X-Api-Key", "key")))
+		t.Cleanup(serv.Close)
+
+		var calls atomic.Int32
+		provider := func(_ context.Context, u *url.URL) (http.Header, error) {
+			calls.Add(1)
+			assert.EqualT(t, "/doc", u.Path)
+
+			return http.Header{"x-api-key": []string{"key"}}, nil
+		}
+
+		for range 2 {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithCredentialsProvider(provider))
+			require.NoError(t, err)
+		}
+
+		assert.EqualT(t, int32(2), calls.Load())
+	})
+
+	t.Run("should refresh credentials on 401", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveBearerTokenFunc("fresh")))
+		t.Cleanup(serv.Close)
+
+		var refreshed bool
+		provider := func(ctx context.Context, _ *url.URL) (http.Header, error) {
+			if IsCredentialsRefresh(ctx) {
+				refreshed = true
+
+				return http.Header{"Authorization": []string{"Bearer fresh"}}, nil
+			}
+
+			return http.Header{"Authorization": []string{"Bearer expired"}}, nil
+		}
+
+		b, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithCredentialsProvider(provider))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("the content"), b)
+		assert.TrueT(t, refreshed)
+	})
+
+	t.Run("should refresh credentials only once", func(t *testing.T) {
+		var requests atomic.Int32
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			rw.WriteHeader(http.StatusUnauthorized)
+		}))
+		t.Cleanup(serv.Close)
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithBearerToken("invalid"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.EqualT(t, int32(2), requests.Load())
+	})
+
+	t.Run("should fail when the provider fails", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveOK))
+		t.Cleanup(serv.Close)
+
+		errProvider := errors.New("no token")
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithCredentialsProvider(func(context.Context, *url.URL) (http.Header, error) {
+			return nil, errProvider
+		}))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		require.ErrorIs(t, err, errProvider)
+	})
+
+	t.Run("with redirects", func(t *testing.T) {
+		var gotAuth, gotKey atomic.Value
+		other := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			gotAuth.Store(r.Header.Get("Authorization"))
+			gotKey.Store(r.Header.Get("X-Api-Key"))
+			serveOK(rw, r)
+		}))
+		t.Cleanup(other.Close)
+
+		var serv *httptest.Server
+		serv = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/cross-host":
+				http.Redirect(rw, r, other.URL+"/doc", http.StatusFound)
+			case "/same-host":
+				http.Redirect(rw, r, serv.URL+"/doc", http.StatusFound)
+			default:
+				gotAuth.Store(r.Header.Get("Authorization"))
+				gotKey.Store(r.Header.Get("X-Api-Key"))
+				serveOK(rw, r)
+			}
+		}))
+		t.Cleanup(serv.Close)
+
+		provider := WithCredentialsProvider(func(context.Context, *url.URL) (http.Header, error) {
+			return http.Header{"Authorization": []string{"Bearer secret"}, "X-Api-Key": []string{"key"}}, nil
+		})
+
+		t.Run("should not send credentials after a cross-host redirect", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/cross-host", provider)
+			require.NoError(t, err)
+			assert.Empty(t, gotAuth.Load())
+			assert.Empty(t, gotKey.Load())
+		})
+
+		t.Run("should not send basic auth after a cross-host redirect", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/cross-host", WithBasicAuth("user", "password"))
+			require.NoError(t, err)
+			assert.Empty(t, gotAuth.Load())
+		})
+
+		t.Run("should not send custom headers after a cross-host redirect", func(t *testing.T) {
+			headers := WithCustomHeaders(map[string]string{"x-api-key": "key"})
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/cross-host", headers)
+			require.NoError(t, err)
+			assert.Empty(t, gotKey.Load())
+
+			_, err = LoadFromFileOrHTTP(serv.URL+"/same-host", headers)
+			require.NoError(t, err)
+			assert.Equal(t, "key", gotKey.Load())
+		})
+
+		t.Run("should send credentials after a same-host redirect", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/same-host", provider)
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer secret", gotAuth.Load())
+			assert.Equal(t, "key", gotKey.Load())
+		})
+
+		t.Run("should apply the redirect policy of the client", func(t *testing.T) {
+			client := &http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/same-host", provider, WithHTTPClient(client))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+	})
+}
+
+func TestHostCredentials(t *testing.T) {
+	var gotAuth, gotKey atomic.Value
+	record := func(rw http.ResponseWriter, r *http.Request) {
+		gotAuth.Store(r.Header.Get("Authorization"))
+		gotKey.Store(r.Header.Get("X-Api-Key"))
+		serveOK(rw, r)
+	}
+
+	other := httptest.NewServer(http.HandlerFunc(record))
+	t.Cleanup(other.Close)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(rw, r, other.URL+"/doc", http.StatusFound)
+
+			return
+		}
+
+		record(rw, r)
+	}))
+	t.Cleanup(serv.Close)
+
+	servHost := mustParseURL(t, serv.URL).Host
+	otherHost := mustParseURL(t, other.URL).Host
+
+	t.Run("should send credentials to a matching host only", func(t *testing.T) {
+		opt := WithHostBasicAuth(servHost, "user", "password")
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", opt)
+		require.NoError(t, err)
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", gotAuth.Load())
+
+		_, err = LoadFromFileOrHTTP(other.URL+"/doc", opt)
+		require.NoError(t, err)
+		assert.Empty(t, gotAuth.Load())
+	})
+
+	t.Run("should prefer host credentials over global credentials", func(t *testing.T) {
+		opts := []Option{
+			WithBearerToken("global"),
+			WithHostCredentials("127.0.0.*", func(context.Context, *url.URL) (http.Header, error) {
+				return http.Header{"Authorization": []string{"Bearer scoped"}}, nil
+			}),
+		}
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", opts...)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer scoped", gotAuth.Load())
+	})
+
+	t.Run("should set host credentials after a redirect to another host", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(serv.URL+"/redirect",
+			hostBearerToken(servHost, "first"),
+			hostBearerToken(otherHost, "second"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer second", gotAuth.Load())
+	})
+
+	t.Run("should send custom headers to a matching host only", func(t *testing.T) {
+		opt := WithHostCustomHeaders(otherHost, map[string]string{"X-Api-Key": "key"})
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", opt)
+		require.NoError(t, err)
+		assert.Empty(t, gotKey.Load())
+
+		_, err = LoadFromFileOrHTTP(serv.URL+"/redirect", opt)
+		require.NoError(t, err)
+		assert.Equal(t, "key", gotKey.Load())
+	})
+
+	t.Run("should use credentials from a netrc file", func(t *testing.T) {
+		netrc := filepath.Join(t.TempDir(), "netrc")
+		require.NoError(t, os.WriteFile(netrc, []byte(
+			"machine 127.0.0.1 login user password password\n",
+		), 0o600))
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithNetrc(netrc))
+		require.NoError(t, err)
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", gotAuth.Load())
+	})
+
+	t.Run("should read a netrc file once, when first needed", func(t *testing.T) {
+		netrc := filepath.Join(t.TempDir(), "netrc")
+		opt := WithNetrc(netrc)
+
+		// the file is not read when the option is applied
+		_, err := JSONDoc("fixtures/petstore_fixture.json", opt)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(netrc, []byte(
+			"machine 127.0.0.1 login user password password\n",
+		), 0o600))
+		_, err = LoadFromFileOrHTTP(serv.URL+"/doc", opt)
+		require.NoError(t, err)
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", gotAuth.Load())
+
+		// later loads reuse the same entries
+		require.NoError(t, os.Remove(netrc))
+		gotAuth.Store("")
+		_, err = LoadFromFileOrHTTP(serv.URL+"/doc", opt)
+		require.NoError(t, err)
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", gotAuth.Load())
+	})
+
+	t.Run("should fail with an invalid netrc file", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithNetrc(filepath.Join(t.TempDir(), "missing")))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should match host patterns", func(t *testing.T) {
+		u := mustParseURL(t, "https://API.example.com:8443/doc")
+
+		assert.TrueT(t, matchHost("api.example.com", u))
+		assert.TrueT(t, matchHost("*.example.com", u))
+		assert.TrueT(t, matchHost("api.example.com:8443", u))
+		assert.FalseT(t, matchHost("api.example.com:443", u))
+		assert.FalseT(t, matchHost("example.com", u))
+		assert.FalseT(t, matchHost("[", u))
+	})
+}
+
+func hostBearerToken(pattern, token string) Option {
+	return WithHostCredentials(pattern, func(context.Context, *url.URL) (http.Header, error) {
+		return http.Header{"Authorization": []string{"Bearer " + token}}, nil
+	})
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+const sniffLen = 16
+
+// Decompressor describes a compression format supported by the loader.
+//
+// Compressed documents are detected by, in this order:
+//   - the "Content-Encoding" of a remote document
+//   - the extension of the path, e.g. ".gz"
+//   - the first bytes of the document ("magic bytes")
+type Decompressor struct {
+	// Encoding is the token of this format used in the HTTP "Content-Encoding" header, e.g. "gzip".
+	Encoding string
+
+	// Extensions are the file extensions of documents compressed with this format, e.g. ".gz".
+	Extensions []string
+
+	// Sniff detects this format from the first bytes of a document.
+	Sniff func(head []byte) bool
+
+	// NewReader builds a reader that decompresses its input.
+	NewReader func(io.Reader) (io.ReadCloser, error)
+}
+
+// builtinDecompressors are the compression formats supported out of the box.
+//
+// The zstd format is recognized, but the standard library provides no decoder for it.
+// A decoder may be plugged with [WithDecompressor].
+var builtinDecompressors = []Decompressor{
+	{
+		Encoding:   "gzip",
+		Extensions: []string{".gz", ".gzip"},
+		Sniff:      hasMagic([]byte{0x1f, 0x8b}),
+		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+	},
+	{
+		Encoding:   "bzip2",
+		Extensions: []string{".bz2"},
+		Sniff:      sniffBzip2,
+		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+			return io.NopCloser(bzip2.NewReader(r)), nil
+		},
+	},
+	{
+		Encoding:   "zstd",
+		Extensions: []string{".zst", ".zstd"},
+		Sniff:      hasMagic([]byte{0x28, 0xb5, 0x2f, 0xfd}),
+	},
+}
+
+// compressionExtensions lists the file extensions of all built-in compression formats.
+var compressionExtensions = func() []string {
+	var exts []string
+	for _, d := range builtinDecompressors {
+		exts = append(exts, d.Extensions...)
+	}
+
+	return exts
+}()
+
+// trimCompressionExt removes a compression extension such as ".gz" from a path.
+func trimCompressionExt(pth string) string {
+	ext := path.Ext(pth)
+	if slices.Contains(compressionExtensions, strings.ToLower(ext)) {
+		return strings.TrimSuffix(pth, ext)
+	}
+
+	return pth
+}
+
+func hasMagic(magic []byte) func([]byte) bool {
+	return func(head []byte) bool {
+		return bytes.HasPrefix(head, magic)
+	}
+}
+
+// sniffBzip2 recognizes the "BZh" signature, followed by the block size and
+// the magic number of either the first block or the end of stream.
+func sniffBzip2(head []byte) bool {
+	const headerLen = 10
+	if len(head) < headerLen || !bytes.HasPrefix(head, []byte("BZh")) || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+
+	magic := head[4:headerLen]
+
+	return bytes.Equal(magic, []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) || // block
+		bytes.Equal(magic, []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}) // end of stream
+}
+
+// decompressors returns the registered decompressors, followed by the built-in ones.
+func (o options) decompressors() []Decompressor {
+	if len(o.customDecompressors) == 0 {
+		return builtinDecompressors
+	}
+
+	return append(slices.Clone(o.customDecompressors), builtinDecompressors...)
+}
+
+// decompressorFor determines which decompressor applies to a document, if any.
+func (o options) decompressorFor(pth, contentEncoding string, head []byte) (*Decompressor, error) {
+	all := o.decompressors()
+
+	if encoding := contentEncodingToken(contentEncoding); encoding != "" {
+		for i := range all {
+			if strings.EqualFold(all[i].Encoding, encoding) || (encoding == "x-gzip" && all[i].Encoding == "gzip") {
+				return &all[i], nil
+			}
+		}
+
+		return nil, fmt.Errorf("unsupported Content-Encoding %q for document at %q: %w", contentEncoding, pth, ErrLoader)
+	}
+
+	ext := strings.ToLower(path.Ext(stripQuery(pth)))
+	for i := range all {
+		if ext != "" && slices.Contains(all[i].Extensions, ext) && (all[i].Sniff == nil || all[i].Sniff(head)) {
+			return &all[i], nil
+		}
+	}
+
+	for i := range all {
+		if all[i].Sniff != nil && all[i].Sniff(head) {
+			return &all[i], nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // no decompression needed
+}
+
+// contentEncodingToken returns the content coding applied to a remote document.
+//
+// The "identity" coding is ignored.
+func contentEncodingToken(contentEncoding string) string {
+	var encoding string
+	for token := range strings.SplitSeq(contentEncoding, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" || token == "identity" {
+			continue
+		}
+
+		if encoding != "" {
+			// several codings are not supported: this will produce an error
+			return contentEncoding
+		}
+
+		encoding = token
+	}
+
+	return encoding
+}
+
+func stripQuery(pth string) string {
+	pth, _, _ = strings.Cut(pth, "?")
+	pth, _, _ = strings.Cut(pth, "#")
+
+	return pth
+}
+
+// decompressReader wraps a reader to decompress its content, whenever a compressed document is detected.
+//
+// The returned close function releases the decompressor. It does not close the underlying reader.
+func (o options) decompressReader(r io.Reader, pth, contentEncoding string) (_ io.Reader, closer func() error, isDecompressed bool, _ error) {
+	noop := func() error { return nil }
+	if o.disableDecompression {
+		return r, noop, false, nil
+	}
+
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(sniffLen) // errors, e.g. io.EOF on short documents, are reported when reading
+
+	d, err := o.decompressorFor(pth, contentEncoding, head)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if d == nil {
+		return br, noop, false, nil
+	}
+
+	if d.NewReader == nil {
+		return nil, nil, false, fmt.Errorf(
+			"document at %q is compressed with %s, but no decoder is available: register one with WithDecompressor: %w",
+			pth, d.Encoding, ErrLoader,
+		)
+	}
+
+	dr, err := d.NewReader(br)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("could not decompress document at %q: %w: %w", pth, err, ErrLoader)
+	}
+
+	return dr, dr.Close, true, nil
+}
+
+// readAllDecompressed reads all the content from a reader, decompressing it if needed,
+// up to limit bytes of decompressed content.
+func (o options) readAllDecompressed(r io.Reader, pth, contentEncoding string) ([]byte, error) {
+	dr, closer, _, err := o.decompressReader(r, pth, contentEncoding)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = closer()
+	}()
+
+	return readAllLimited(dr, pth, o.maxBytes)
+}
+
+// decompressBytes decompresses some already loaded content, if needed.
+func (o options) decompressBytes(data []byte, pth string) ([]byte, error) {
+	if o.disableDecompression {
+		return checkMaxBytes(data, pth, o.maxBytes)
+	}
+
+	head := data[:min(len(data), sniffLen)]
+	d, err := o.decompressorFor(pth, "", head)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		return checkMaxBytes(data, pth, o.maxBytes)
+	}
+
+	return o.readAllDecompressed(bytes.NewReader(data), pth, "")
+}
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestDecompression(t *testing.T) {
+	gzipped := mustGzip(t, jsonPetStore)
+
+	t.Run("with local documents", func(t *testing.T) {
+		t.Run("should decompress a gzip file", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.json.gz", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+
+		t.Run("should decompress a bzip2 file", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.yaml.bz2", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+		})
+
+		t.Run("should decompress a gzip file from the os file system", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.json.gz", WithMaxBytes(1<<20))
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+
+		t.Run("should load a compressed YAML document", func(t *testing.T) {
+			doc, err := YAMLDoc("fixtures/petstore_fixture.yaml.bz2", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should not decompress when disabled", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.json.gz", WithFS(embeddedFixtures), WithDecompression(false))
+			require.NoError(t, err)
+			assert.Equal(t, gzipped[:2], b[:2])
+		})
+
+		t.Run("should fail on zstd without a decoder", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.yaml.zst", WithFS(embeddedFixtures))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "WithDecompressor")
+		})
+
+		t.Run("should decompress zstd with a registered decoder", func(t *testing.T) {
+			var called bool
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.yaml.zst",
+				WithFS(embeddedFixtures),
+				WithDecompressor(Decompressor{
+					Encoding: "zstd",
+					NewReader: func(io.Reader) (io.ReadCloser, error) {
+						called = true // fake decoder
+
+						return io.NopCloser(bytes.NewReader(yamlPetStore)), nil
+					},
+				}),
+			)
+			require.NoError(t, err)
+			assert.TrueT(t, called)
+			assert.Equal(t, yamlPetStore, b)
+		})
+
+		t.Run("should stream a decompressed document", func(t *testing.T) {
+			rdr, meta, err := Open("fixtures/petstore_fixture.json.gz", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+			assert.EqualT(t, int64(-1), meta.Size)
+			assert.EqualT(t, "application/json", meta.ContentType)
+		})
+	})
+
+	t.Run("with remote documents", func(t *testing.T) {
+		t.Run("should decompress with Content-Encoding", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("Content-Encoding", "gzip")
+				_, _ = rw.Write(gzipped)
+			}))
+			defer ts.Close()
+
+			b, err := LoadFromFileOrHTTP(ts.URL + "/openapi")
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+
+		t.Run("should decompress by extension", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = rw.Write(gzipped)
+			}))
+			defer ts.Close()
+
+			doc, err := Doc(ts.URL + "/spec.json.gz?version=1")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should decompress by magic bytes", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write(gzipped)
+			}))
+			defer ts.Close()
+
+			rdr, _, err := Open(ts.URL + "/openapi")
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+
+		t.Run("should fail on an unsupported Content-Encoding", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("Content-Encoding", "br")
+				_, _ = rw.Write([]byte("not really brotli"))
+			}))
+			defer ts.Close()
+
+			_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(RetryPolicy{MaxAttempts: 3}))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), `unsupported Content-Encoding "br"`)
+		})
+
+		t.Run("should protect against zip bombs", func(t *testing.T) {
+			bomb := mustGzip(t, bytes.Repeat([]byte(" "), 1<<20))
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write(bomb)
+			}))
+			defer ts.Close()
+
+			require.Less(t, len(bomb), 4096)
+			_, err := LoadFromFileOrHTTP(ts.URL+"/bomb.json.gz", WithMaxBytes(4096))
+			requireMaxBytesError(t, err, 4096)
+		})
+	})
+
+	t.Run("should decompress a data URI", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP("data:application/gzip," + urlPathEscape(gzipped))
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+	})
+}
+
+func TestMatchersWithCompression(t *testing.T) {
+	assert.TrueT(t, JSONMatcher("spec.json.gz"))
+	assert.TrueT(t, JSONMatcher("spec.json.zst"))
+	assert.TrueT(t, YAMLMatcher("spec.yaml.bz2"))
+	assert.TrueT(t, YAMLMatcher("spec.yml.GZ"))
+	assert.FalseT(t, JSONMatcher("spec.gz"))
+	assert.FalseT(t, YAMLMatcher("spec.json.gz"))
+}
+
+func TestSniffCompression(t *testing.T) {
+	o := optionsWithDefaults(nil)
+
+	t.Run("should not mistake YAML for bzip2", func(t *testing.T) {
+		d, err := o.decompressorFor("spec", "", []byte("BZh9: looks like bzip2"))
+		require.NoError(t, err)
+		assert.Nil(t, d)
+	})
+
+	t.Run("should ignore identity Content-Encoding", func(t *testing.T) {
+		d, err := o.decompressorFor("spec", "identity", []byte("{}"))
+		require.NoError(t, err)
+		assert.Nil(t, d)
+	})
+
+	t.Run("should accept x-gzip Content-Encoding", func(t *testing.T) {
+		d, err := o.decompressorFor("spec", "x-gzip", nil)
+		require.NoError(t, err)
+		require.NotNil(t, d)
+		assert.EqualT(t, "gzip", d.Encoding)
+	})
+
+	t.Run("should reject several Content-Encodings", func(t *testing.T) {
+		_, err := o.decompressorFor("spec", "gzip, br", nil)
+		require.Error(t, err)
+	})
+}
+
+func mustGzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func urlPathEscape(data []byte) string {
+	var b strings.Builder
+	for _, c := range data {
+		b.WriteString("%")
+		b.WriteString(strings.ToUpper(string("0123456789abcdef"[c>>4]) + string("0123456789abcdef"[c&0xf])))
+	}
+
+	return b.String()
+}
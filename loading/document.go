@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/swag/jsonutils"
+	"github.com/go-openapi/swag/yamlutils"
+)
+
+// acceptDocument is the "Accept" header sent by [Doc] to negotiate the format of a remote document.
+const acceptDocument = "application/json, application/yaml;q=0.9, application/x-yaml;q=0.9, text/yaml;q=0.8, */*;q=0.5"
+
+type docFormat uint8
+
+const (
+	formatUnknown docFormat = iota
+	formatJSON
+	formatYAML
+)
+
+// Doc loads a JSON or YAML document from either a file or a remote url, and returns it as JSON.
+//
+// Unlike [JSONDoc] or [YAMLDoc], the caller doesn't need to know the format of the document in advance.
+//
+// The format is determined by, in this order:
+//   - the "Content-Type" of a remote document, or the media type of a data URI
+//   - the extension of the path (see [JSONMatcher] and [YAMLMatcher]), ignoring any query string or fragment
+//   - sniffing the content: a document starting with '{' or '[' is JSON, anything else is YAML
+//
+// When fetching a remote document, an "Accept" header favoring JSON and YAML media types is sent,
+// unless overridden by [WithCustomHeaders].
+//
+// Documents encoded in UTF-16 or UTF-32, or starting with a byte order mark, are converted to UTF-8.
+// This also applies to [JSONDoc], [YAMLDoc], [YAMLData] and [YAMLDocs], but not to raw bytes.
+//
+// JSONC and JSON5 documents, with a ".jsonc" or ".json5" extension, are converted to strict JSON
+// (see [jsonutils.RelaxedToJSON]). This also applies to [JSONDoc].
+func Doc(path string, opts ...Option) (json.RawMessage, error) {
+	return DocContext(context.Background(), path, opts...)
+}
+
+// DocContext loads a JSON or YAML document from either a file or a remote url, and returns it as JSON, with a context.
+//
+// See [Doc].
+func DocContext(ctx context.Context, path string, opts ...Option) (json.RawMessage, error) {
+	o := optionsWithDefaults(opts)
+	o.accept = acceptDocument
+
+	return observeLoad(ctx, o, path, func() (json.RawMessage, error) {
+		return loadDocument(ctx, path, o)
+	})
+}
+
+func loadDocument(ctx context.Context, path string, o options) (json.RawMessage, error) {
+	path, expected := o.splitIntegrity(path)
+
+	var contentType string
+	remote := func(p string) ([]byte, error) {
+		result, err := loadHTTP(ctx, p, o)
+		if err != nil {
+			return nil, err
+		}
+		contentType = result.header.Get("Content-Type")
+
+		return result.data, nil
+	}
+
+	data, err := strategyLoader{options: o, remote: remote}.load(ctx, path)
+	if err != nil {
+		return nil, errors.Join(err, ErrLoader)
+	}
+
+	if err = o.verifyIntegrity(path, data, expected); err != nil {
+		return nil, err
+	}
+
+	if data, err = toUTF8(path, data); err != nil {
+		return nil, err
+	}
+
+	if scheme, _ := uriScheme(path); scheme == schemeData {
+		contentType = dataURIMediaType(path)
+	}
+
+	switch detectFormat(path, contentType, data) {
+	case formatJSON:
+		if data, err = relaxJSON(path, data); err != nil {
+			return nil, err
+		}
+
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("invalid JSON document at %q: %w", path, ErrLoader)
+		}
+
+		return o.interpolateJSON(path, data)
+	default:
+		yamlDoc, err := yamlutils.BytesToYAMLDoc(data)
+		if err != nil {
+			return nil, errors.Join(err, ErrLoader)
+		}
+
+		if err = o.interpolateYAML(path, yamlDoc); err != nil {
+			return nil, err
+		}
+
+		return yamlutils.YAMLToJSON(yamlDoc)
+	}
+}
+
+// toUTF8 converts a document in UTF-16 or UTF-32, or with a byte order mark, to UTF-8.
+//
+// See [jsonutils.ToUTF8].
+func toUTF8(path string, data []byte) ([]byte, error) {
+	decoded, err := jsonutils.ToUTF8(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encoding for document at %q: %w: %w", path, err, ErrLoader)
+	}
+
+	return decoded, nil
+}
+
+// detectFormat determines the format of a document, from its media type, its path or its content.
+func detectFormat(path, contentType string, data []byte) docFormat {
+	if format := formatFromMediaType(contentType); format != formatUnknown {
+		return format
+	}
+
+	if format := formatFromPath(path); format != formatUnknown {
+		return format
+	}
+
+	return sniffFormat(data)
+}
+
+func formatFromMediaType(contentType string) docFormat {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return formatUnknown
+	}
+
+	switch {
+	case mediaType == "application/json", mediaType == "text/json", strings.HasSuffix(mediaType, "+json"):
+		return formatJSON
+	case mediaType == "application/yaml", mediaType == "application/x-yaml",
+		mediaType == "text/yaml", mediaType == "text/x-yaml", strings.HasSuffix(mediaType, "+yaml"):
+		return formatYAML
+	default:
+		return formatUnknown
+	}
+}
+
+func formatFromPath(path string) docFormat {
+	path = documentPath(path)
+
+	switch {
+	case JSONMatcher(path):
+		return formatJSON
+	case YAMLMatcher(path):
+		return formatYAML
+	default:
+		return formatUnknown
+	}
+}
+
+// documentPath returns the path of a document, without any query string or fragment.
+func documentPath(path string) string {
+	if u, err := url.Parse(path); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		return u.Path
+	}
+
+	path, _, _ = strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+
+	return path
+}
+
+func sniffFormat(data []byte) docFormat {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return formatJSON
+	}
+
+	return formatYAML
+}
+
+// dataURIMediaType returns the media type declared by a data URI.
+func dataURIMediaType(uri string) string {
+	_, rest, _ := strings.Cut(uri, ":")
+	header, _, _ := strings.Cut(rest, ",")
+	header = strings.TrimSuffix(header, ";base64")
+
+	return header
+}
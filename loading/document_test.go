@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestDoc(t *testing.T) {
+	t.Run("with remote documents", func(t *testing.T) {
+		for _, tc := range []struct {
+			Title       string
+			Path        string
+			ContentType string
+			Content     []byte
+		}{
+			{Title: "should load JSON by content type", Path: "/openapi", ContentType: "application/json; charset=utf-8", Content: jsonPetStore},
+			{Title: "should load JSON by structured suffix", Path: "/openapi", ContentType: "application/vnd.oai.openapi+json", Content: jsonPetStore},
+			{Title: "should load YAML by content type", Path: "/spec?format=yaml", ContentType: "application/yaml", Content: yamlPetStore},
+			{Title: "should load YAML by legacy content type", Path: "/spec", ContentType: "text/x-yaml", Content: yamlPetStore},
+			{Title: "should load YAML by extension", Path: "/spec.yaml?version=2", ContentType: "text/plain", Content: yamlPetStore},
+			{Title: "should load JSON by extension", Path: "/spec.json", ContentType: "application/octet-stream", Content: jsonPetStore},
+			{Title: "should sniff JSON content", Path: "/openapi", ContentType: "text/plain", Content: jsonPetStore},
+			{Title: "should sniff YAML content", Path: "/openapi", ContentType: "text/plain", Content: yamlPetStore},
+			{Title: "should favor content type over extension", Path: "/spec.json", ContentType: "application/yaml", Content: yamlPetStore},
+		} {
+			t.Run(tc.Title, func(t *testing.T) {
+				var accept string
+				ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+					accept = r.Header.Get("Accept")
+					rw.Header().Set("Content-Type", tc.ContentType)
+					_, _ = rw.Write(tc.Content)
+				}))
+				defer ts.Close()
+
+				doc, err := Doc(ts.URL + tc.Path)
+				require.NoError(t, err)
+				assert.JSONEqBytes(t, jsonPetStore, doc)
+				assert.EqualT(t, acceptDocument, accept)
+			})
+		}
+
+		t.Run("should let custom headers override Accept", func(t *testing.T) {
+			var accept string
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				serveJSONPetStore(rw, r)
+			}))
+			defer ts.Close()
+
+			_, err := Doc(ts.URL, WithCustomHeaders(map[string]string{"Accept": "application/json"}))
+			require.NoError(t, err)
+			assert.EqualT(t, "application/json", accept)
+		})
+
+		t.Run("should not send Accept with other loaders", func(t *testing.T) {
+			var accept string
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				serveJSONPetStore(rw, r)
+			}))
+			defer ts.Close()
+
+			_, err := JSONDoc(ts.URL)
+			require.NoError(t, err)
+			assert.Empty(t, accept)
+		})
+
+		t.Run("should not share cached representations with other loaders", func(t *testing.T) {
+			// the server serves YAML when an "Accept" header is sent, and JSON otherwise
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("ETag", `"v1"`)
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					rw.WriteHeader(http.StatusNotModified)
+
+					return
+				}
+
+				if r.Header.Get("Accept") != "" {
+					serveYAMLPetStore(rw, r)
+
+					return
+				}
+
+				serveJSONPetStore(rw, r)
+			}))
+			defer ts.Close()
+			cache := NewMemoryCache()
+
+			doc, err := Doc(ts.URL, WithCache(cache))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+
+			doc, err = JSONDoc(ts.URL, WithCache(cache))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+
+			doc, err = Doc(ts.URL, WithCache(cache))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should fail on invalid JSON", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = rw.Write([]byte(`{"a":`))
+			}))
+			defer ts.Close()
+
+			_, err := Doc(ts.URL)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should fail on a remote error", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(serveKO))
+			defer ts.Close()
+
+			_, err := DocContext(context.Background(), ts.URL)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+	})
+
+	t.Run("with local documents", func(t *testing.T) {
+		t.Run("should load a JSON file", func(t *testing.T) {
+			doc, err := Doc("fixtures/petstore_fixture.json", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should load a YAML file", func(t *testing.T) {
+			doc, err := Doc("fixtures/petstore_fixture.yaml", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, jsonPetStore, doc)
+		})
+
+		t.Run("should fail on invalid YAML", func(t *testing.T) {
+			_, err := Doc("data:,-%20a%0A-%20b")
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+	})
+
+	t.Run("should load a data URI by media type", func(t *testing.T) {
+		doc, err := Doc("data:application/yaml;base64,YTogYgo=")
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"a":"b"}`, string(doc))
+	})
+
+	t.Run("with documents in other encodings", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"bom.json":    &fstest.MapFile{Data: append([]byte{0xEF, 0xBB, 0xBF}, `{"a":"b"}`...)},
+			"utf16.json":  &fstest.MapFile{Data: []byte{0xFF, 0xFE, '{', 0, '}', 0}},
+			"utf16.yaml":  &fstest.MapFile{Data: []byte{0, 'a', 0, ':', 0, ' ', 0, 'b'}},
+			"sniffed":     &fstest.MapFile{Data: []byte{0xFF, 0xFE, '[', 0, ']', 0}},
+			"stream.yaml": &fstest.MapFile{Data: []byte{0xFF, 0xFE, 0, 0, 'a', 0, 0, 0, ':', 0, 0, 0, ' ', 0, 0, 0, 'b', 0, 0, 0}},
+			"truncated":   &fstest.MapFile{Data: []byte{0xFE, 0xFF, 0}},
+		}
+
+		t.Run("should convert documents to UTF-8", func(t *testing.T) {
+			doc, err := Doc("bom.json", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqT(t, `{"a":"b"}`, string(doc))
+
+			doc, err = JSONDoc("bom.json", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqT(t, `{"a":"b"}`, string(doc))
+
+			doc, err = JSONDoc("utf16.json", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqT(t, `{}`, string(doc))
+
+			doc, err = Doc("sniffed", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqT(t, `[]`, string(doc))
+
+			doc, err = YAMLDoc("utf16.yaml", WithFS(fsys))
+			require.NoError(t, err)
+			assert.JSONEqT(t, `{"a":"b"}`, string(doc))
+
+			docs, err := YAMLDocs("stream.yaml", WithFS(fsys))
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.JSONEqT(t, `{"a":"b"}`, string(docs[0]))
+		})
+
+		t.Run("should not convert raw bytes", func(t *testing.T) {
+			data, err := LoadFromFileOrHTTP("bom.json", WithFS(fsys))
+			require.NoError(t, err)
+			assert.Equal(t, fsys["bom.json"].Data, data)
+		})
+
+		t.Run("should fail on a truncated document", func(t *testing.T) {
+			_, err := Doc("truncated", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "invalid encoding")
+		})
+	})
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.EqualT(t, formatJSON, sniffFormat([]byte("\ufeff  \n[1,2]")))
+	assert.EqualT(t, formatYAML, sniffFormat([]byte("a: b")))
+	assert.EqualT(t, formatYAML, sniffFormat(nil))
+	assert.EqualT(t, formatUnknown, formatFromMediaType("not a media type;;"))
+	assert.EqualT(t, formatYAML, formatFromPath("https://example.com/spec.yml#/definitions"))
+	assert.EqualT(t, formatJSON, formatFromPath("folder/spec.json?x=y"))
+	assert.EqualT(t, formatUnknown, formatFromPath(`C:\folder\spec`))
+	assert.EqualT(t, "application/yaml", dataURIMediaType("data:application/yaml;base64,xyz"))
+}
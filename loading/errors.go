@@ -3,6 +3,13 @@
 
 package loading
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
 type loadingError string
 
 const (
@@ -13,3 +20,152 @@ const (
 func (e loadingError) Error() string {
 	return string(e)
 }
+
+// MaxBytesError is raised when a document exceeds the maximum size set by [WithMaxBytes].
+//
+// It wraps [ErrLoader].
+type MaxBytesError struct {
+	// Path is the path or URL of the document.
+	Path string
+
+	// Limit is the maximum size in bytes that was exceeded.
+	Limit int64
+}
+
+func (e *MaxBytesError) Error() string {
+	return fmt.Sprintf("document at %q exceeds the maximum size of %d bytes: %v", e.Path, e.Limit, ErrLoader)
+}
+
+func (e *MaxBytesError) Unwrap() error {
+	return ErrLoader
+}
+
+// maxBodySnippet is the maximum size of the response body retained by a [LoadError].
+const maxBodySnippet = 512
+
+// LoadError is raised when a document cannot be accessed, e.g. when a server responds with
+// an unexpected HTTP status code, or when a local file cannot be read.
+//
+// It wraps [ErrLoader], as well as the underlying cause, if any.
+type LoadError struct {
+	// Path is the path or URL of the document, as requested.
+	Path string
+
+	// URL is the resolved location of the document: the final URL after redirects for a remote document,
+	// or the file path for a local document.
+	URL string
+
+	// StatusCode is the HTTP status code of the response, or 0 if no response was received.
+	StatusCode int
+
+	// Header holds the headers of the response, if any.
+	Header http.Header
+
+	// Body is the beginning of the response body, truncated to 512 bytes.
+	Body []byte
+
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("could not access document at %q [%d %s]: %v", e.Path, e.StatusCode, http.StatusText(e.StatusCode), ErrLoader)
+	}
+
+	if e.Err == nil {
+		return fmt.Sprintf("could not access document at %q: %v", e.Path, ErrLoader)
+	}
+
+	if errors.Is(e.Err, ErrLoader) {
+		return fmt.Sprintf("could not access document at %q: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("could not access document at %q: %v: %v", e.Path, e.Err, ErrLoader)
+}
+
+func (e *LoadError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrLoader}
+	}
+
+	return []error{e.Err, ErrLoader}
+}
+
+// ForbiddenHostError is raised when a remote document is hosted on a host that is not permitted by
+// [WithAllowedHosts], [WithDeniedHosts] or [WithBlockPrivateNetworks].
+//
+// It wraps [ErrLoader].
+type ForbiddenHostError struct {
+	// Host is the host, or the resolved address, that was refused.
+	Host string
+
+	// Reason explains why the host was refused.
+	Reason string
+}
+
+func (e *ForbiddenHostError) Error() string {
+	return fmt.Sprintf("access to host %q is forbidden: %s: %v", e.Host, e.Reason, ErrLoader)
+}
+
+func (e *ForbiddenHostError) Unwrap() error {
+	return ErrLoader
+}
+
+// IntegrityError is raised when a document does not match its expected integrity,
+// set with [WithIntegrity], with an "#integrity=" fragment, or recorded in a [Lockfile].
+//
+// It wraps [ErrLoader].
+type IntegrityError struct {
+	// Path is the path or URL of the document.
+	Path string
+
+	// Expected is the expected integrity, e.g. "sha256-{base64 digest}".
+	Expected string
+
+	// Actual is the digest of the loaded document.
+	Actual string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("document at %q does not match its integrity %q (got %q): %v", e.Path, e.Expected, e.Actual, ErrLoader)
+}
+
+func (e *IntegrityError) Unwrap() error {
+	return ErrLoader
+}
+
+// InterpolationError is raised when placeholders in a document refer to undefined variables,
+// with [WithInterpolation].
+//
+// It wraps [ErrLoader].
+type InterpolationError struct {
+	// Path is the path or URL of the document.
+	Path string
+
+	// Missing lists every placeholder with an undefined variable, in the order of the document.
+	Missing []MissingVariable
+}
+
+// MissingVariable is an undefined variable, referred to by a placeholder in a document.
+type MissingVariable struct {
+	// Name is the name of the variable.
+	Name string
+
+	// Pointer is the location of the string value with the placeholder, as a JSON Pointer (RFC 6901),
+	// e.g. "/servers/0/url".
+	Pointer string
+}
+
+func (e *InterpolationError) Error() string {
+	missing := make([]string, 0, len(e.Missing))
+	for _, variable := range e.Missing {
+		missing = append(missing, fmt.Sprintf("%s at %q", variable.Name, variable.Pointer))
+	}
+
+	return fmt.Sprintf("undefined variables in document at %q: %s: %v", e.Path, strings.Join(missing, ", "), ErrLoader)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return ErrLoader
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestLoadError(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(rw, r, "/missing", http.StatusFound)
+		case "/large":
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte(strings.Repeat("x", 2*maxBodySnippet)))
+		default:
+			rw.Header().Set("X-Request-Id", "abc")
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte("no such document"))
+		}
+	}))
+	t.Cleanup(serv.Close)
+
+	t.Run("should report the status of a remote document", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(serv.URL + "/moved")
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.EqualT(t, serv.URL+"/moved", loadErr.Path)
+		assert.EqualT(t, serv.URL+"/missing", loadErr.URL)
+		assert.EqualT(t, http.StatusNotFound, loadErr.StatusCode)
+		assert.EqualT(t, "abc", loadErr.Header.Get("X-Request-Id"))
+		assert.Equal(t, []byte("no such document"), loadErr.Body)
+		assert.EqualT(t,
+			`could not access document at "`+serv.URL+`/moved" [404 Not Found]: loader error`,
+			err.Error(),
+		)
+	})
+
+	t.Run("should truncate the response body", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(serv.URL + "/large")
+		require.Error(t, err)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.EqualT(t, http.StatusInternalServerError, loadErr.StatusCode)
+		assert.Len(t, loadErr.Body, maxBodySnippet)
+	})
+
+	t.Run("should report a transport error", func(t *testing.T) {
+		closed := httptest.NewServer(http.HandlerFunc(serveOK))
+		closed.Close()
+
+		_, err := LoadFromFileOrHTTP(closed.URL + "/doc")
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.EqualT(t, 0, loadErr.StatusCode)
+		require.Error(t, loadErr.Err)
+	})
+
+	t.Run("should report a missing local file", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP("file://fixtures/missing.yaml")
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.EqualT(t, "file://fixtures/missing.yaml", loadErr.Path)
+		assert.EqualT(t, "fixtures/missing.yaml", loadErr.URL)
+
+		_, _, err = Open("fixtures/missing.yaml")
+		require.ErrorAs(t, err, &loadErr)
+	})
+
+	t.Run("should not repeat ErrLoader in the message", func(t *testing.T) {
+		err := &LoadError{Path: "doc.yaml", Err: &MaxBytesError{Path: "doc.yaml", Limit: 10}}
+		assert.EqualT(t, 1, strings.Count(err.Error(), ErrLoader.Error()))
+		require.ErrorIs(t, err, ErrLoader)
+
+		err = &LoadError{Path: "doc.yaml", Err: errors.New("boom")}
+		assert.EqualT(t, `could not access document at "doc.yaml": boom: loader error`, err.Error())
+	})
+}
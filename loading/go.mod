@@ -1,16 +1,16 @@
 module github.com/go-openapi/swag/loading
 
 require (
+	github.com/go-openapi/swag/jsonutils v0.25.5
 	github.com/go-openapi/swag/yamlutils v0.25.5
 	github.com/go-openapi/testify/enable/yaml/v2 v2.4.0
 	github.com/go-openapi/testify/v2 v2.4.0
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
 	github.com/go-openapi/swag/conv v0.25.5 // indirect
-	github.com/go-openapi/swag/jsonutils v0.25.5 // indirect
 	github.com/go-openapi/swag/typeutils v0.25.5 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 )
 
 replace (
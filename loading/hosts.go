@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultMaxRedirects is the number of redirects followed by default, like the [http.Client] does.
+const defaultMaxRedirects = 10
+
+// checkHost verifies that the host of a URL is allowed by [WithAllowedHosts] and [WithDeniedHosts].
+func (o options) checkHost(u *url.URL) error {
+	if slices.ContainsFunc(o.deniedHosts, func(pattern string) bool { return matchHost(pattern, u) }) {
+		return &ForbiddenHostError{Host: u.Host, Reason: "the host is denied"}
+	}
+
+	if len(o.allowedHosts) > 0 &&
+		!slices.ContainsFunc(o.allowedHosts, func(pattern string) bool { return matchHost(pattern, u) }) {
+		return &ForbiddenHostError{Host: u.Host, Reason: "the host is not allowed"}
+	}
+
+	return nil
+}
+
+// matchHost tells if the host of a URL matches a pattern.
+//
+// The pattern is matched without regard to case, with the syntax of [path.Match].
+// A pattern with a port only matches this port.
+func matchHost(pattern string, u *url.URL) bool {
+	host := u.Hostname()
+	if strings.Contains(pattern, ":") {
+		host = u.Host
+	}
+
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(host))
+
+	return err == nil && matched
+}
+
+// withGuardedClient prepares the HTTP client that refuses to connect to private networks,
+// if enabled by [WithBlockPrivateNetworks].
+//
+// The client is built once, when first needed, so that all the loads with these options reuse its connections.
+func (o options) withGuardedClient() options {
+	if !o.blockPrivateNetworks {
+		o.guardedClient = nil
+
+		return o
+	}
+
+	client := o.client
+	o.guardedClient = sync.OnceValues(func() (*http.Client, error) {
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("blocking private networks requires the HTTP client to use an *http.Transport: %w", ErrLoader)
+		}
+
+		guarded := *client
+		guarded.Transport = guardedTransport(base)
+
+		return &guarded, nil
+	})
+
+	return o
+}
+
+// guardedTransport returns a copy of an [http.Transport] that refuses to connect to private network addresses.
+//
+// The check occurs when dialing, after the host name is resolved, so that DNS rebinding cannot bypass it.
+// Proxies are disabled, since they would resolve the host name on our behalf.
+func guardedTransport(base *http.Transport) *http.Transport {
+	const (
+		dialTimeout = 30 * time.Second
+		keepAlive   = 30 * time.Second
+	)
+
+	transport := base.Clone()
+	transport.Proxy = nil
+	transport.DialTLSContext = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: keepAlive,
+		Control:   refusePrivateNetworks,
+	}).DialContext
+
+	return transport
+}
+
+// refusePrivateNetworks is a [net.Dialer] control function, called with the resolved address.
+func refusePrivateNetworks(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return &ForbiddenHostError{Host: host, Reason: "the address could not be verified"}
+	}
+
+	if isPrivateAddr(addr) {
+		return &ForbiddenHostError{Host: host, Reason: "private, loopback and link-local addresses are blocked"}
+	}
+
+	return nil
+}
+
+func isPrivateAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsPrivate() ||
+		addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified()
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestHostPolicy(t *testing.T) {
+	var requests atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		serveOK(rw, r)
+	}))
+	t.Cleanup(other.Close)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(rw, r, other.URL+"/doc", http.StatusFound)
+		case "/loop":
+			http.Redirect(rw, r, "/loop", http.StatusFound)
+		default:
+			serveOK(rw, r)
+		}
+	}))
+	t.Cleanup(serv.Close)
+
+	otherHost := mustParseURL(t, other.URL).Host
+
+	t.Run("with allowed and denied hosts", func(t *testing.T) {
+		t.Run("should refuse a denied host", func(t *testing.T) {
+			requests.Store(0)
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithDeniedHosts("127.0.0.1"), WithRetry(RetryPolicy{MaxAttempts: 3}))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+
+			var forbidden *ForbiddenHostError
+			require.ErrorAs(t, err, &forbidden)
+			assert.EqualT(t, int32(0), requests.Load())
+		})
+
+		t.Run("should refuse a host that is not allowed", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithAllowedHosts("*.example.com"))
+			require.Error(t, err)
+
+			var forbidden *ForbiddenHostError
+			require.ErrorAs(t, err, &forbidden)
+		})
+
+		t.Run("should load from an allowed host", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithAllowedHosts("*.example.com", "127.0.0.1"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("the content"), b)
+		})
+
+		t.Run("should prefer denied hosts over allowed hosts", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithAllowedHosts("127.0.0.1"), WithDeniedHosts("127.0.0.*"))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should check redirects", func(t *testing.T) {
+			requests.Store(0)
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/redirect", WithDeniedHosts(otherHost))
+			require.Error(t, err)
+
+			var forbidden *ForbiddenHostError
+			require.ErrorAs(t, err, &forbidden)
+			assert.EqualT(t, otherHost, forbidden.Host)
+			assert.EqualT(t, int32(1), requests.Load())
+		})
+	})
+
+	t.Run("with private networks blocked", func(t *testing.T) {
+		t.Run("should refuse to connect to a loopback address", func(t *testing.T) {
+			requests.Store(0)
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithBlockPrivateNetworks(true), WithRetry(RetryPolicy{MaxAttempts: 3}))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+
+			var forbidden *ForbiddenHostError
+			require.ErrorAs(t, err, &forbidden)
+			assert.EqualT(t, "127.0.0.1", forbidden.Host)
+			assert.EqualT(t, int32(0), requests.Load())
+		})
+
+		t.Run("should check the resolved address of a host name", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("http://localhost:"+mustParseURL(t, serv.URL).Port()+"/doc", WithBlockPrivateNetworks(true))
+			require.Error(t, err)
+
+			var forbidden *ForbiddenHostError
+			require.ErrorAs(t, err, &forbidden)
+		})
+
+		t.Run("should require an http.Transport", func(t *testing.T) {
+			client := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/doc", WithBlockPrivateNetworks(true), WithHTTPClient(client))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "http.Transport")
+		})
+
+		t.Run("should build the guarded transport once per options", func(t *testing.T) {
+			o := optionsWithDefaults([]Option{WithBlockPrivateNetworks(true)})
+
+			first, err := o.httpClient(requestCredentials{})
+			require.NoError(t, err)
+			second, err := o.httpClient(requestCredentials{})
+			require.NoError(t, err)
+			assert.True(t, first.Transport == second.Transport)
+
+			other, err := optionsWithDefaults([]Option{WithBlockPrivateNetworks(true)}).httpClient(requestCredentials{})
+			require.NoError(t, err)
+			assert.True(t, first.Transport != other.Transport)
+		})
+
+		t.Run("should limit the connections of a guarded transport", func(t *testing.T) {
+			l := NewBatchLoader(WithBlockPrivateNetworks(true), WithMaxConnsPerHost(2))
+
+			client, err := l.options.httpClient(requestCredentials{})
+			require.NoError(t, err)
+			transport, ok := client.Transport.(*http.Transport)
+			require.TrueT(t, ok)
+			assert.EqualT(t, 2, transport.MaxConnsPerHost)
+			assert.Nil(t, transport.Proxy)
+		})
+
+		t.Run("should refuse private addresses", func(t *testing.T) {
+			for _, address := range []string{
+				"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "192.168.0.1:80", "172.16.0.1:80",
+				"169.254.169.254:80", "[fe80::1]:80", "[fd00::1]:80", "[::ffff:127.0.0.1]:80", "0.0.0.0:80",
+			} {
+				require.Error(t, refusePrivateNetworks("tcp", address, nil), address)
+			}
+
+			require.NoError(t, refusePrivateNetworks("tcp", "8.8.8.8:443", nil))
+			require.NoError(t, refusePrivateNetworks("tcp", "[2001:4860:4860::8888]:443", nil))
+			assert.FalseT(t, isPrivateAddr(netip.MustParseAddr("1.1.1.1")))
+		})
+	})
+
+	t.Run("with redirects", func(t *testing.T) {
+		t.Run("should follow redirects", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP(serv.URL + "/redirect")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("the content"), b)
+		})
+
+		t.Run("should stop after the maximum number of redirects", func(t *testing.T) {
+			requests.Store(0)
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/loop", WithMaxRedirects(2), WithRetry(RetryPolicy{MaxAttempts: 3}))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "stopped after 2 redirects")
+			assert.EqualT(t, int32(3), requests.Load())
+		})
+
+		t.Run("should not follow redirects when disabled", func(t *testing.T) {
+			requests.Store(0)
+
+			_, err := LoadFromFileOrHTTP(serv.URL+"/redirect", WithMaxRedirects(0))
+			require.Error(t, err)
+			assert.EqualT(t, int32(1), requests.Load())
+		})
+	})
+}
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func loadHTTPBytes(ctx context.Context, o options) func(path string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		result, err := loadHTTP(ctx, path, o)
+		if err != nil {
+			return nil, err
+		}
+
+		return result.data, nil
+	}
+}
+
+// loadHTTP loads a remote document, possibly from the cache.
+func loadHTTP(ctx context.Context, path string, o options) (httpResult, error) {
+	if o.httpTimeout > 0 {
+		// the timeout is a budget for all attempts
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, o.httpTimeout)
+		defer cancel()
+	}
+
+	if o.cache != nil || o.cacheOnly {
+		return loadCachedHTTP(ctx, path, o)
+	}
+
+	return fetchHTTPWithRetry(ctx, path, o, nil)
+}
+
+// httpResult is the outcome of a successful remote fetch.
+type httpResult struct {
+	data        []byte
+	header      http.Header
+	url         string
+	notModified bool
+}
+
+// fetchHTTPWithRetry fetches a remote document, retrying according to the [RetryPolicy].
+func fetchHTTPWithRetry(ctx context.Context, path string, o options, cached *CacheEntry) (httpResult, error) {
+	return withRetry(ctx, o, func() (httpResult, error) {
+		return fetchHTTP(ctx, path, o, cached)
+	})
+}
+
+// withRetry calls fetch until it succeeds, or until the [RetryPolicy] says it is time to give up.
+func withRetry[T any](ctx context.Context, o options, fetch func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := fetch()
+		if err == nil {
+			return result, nil
+		}
+
+		wait, canRetry := o.retry.next(ctx, attempt, err)
+		if !canRetry {
+			return result, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchHTTP carries out a single attempt to fetch a remote document.
+//
+// If a cached entry is provided, the request is conditional.
+func fetchHTTP(ctx context.Context, path string, o options, cached *CacheEntry) (httpResult, error) {
+	resp, err := doHTTP(ctx, path, o, cached)
+	if err != nil {
+		return httpResult{}, err
+	}
+	defer o.closeResponse(resp)
+
+	finalURL := resp.Request.URL.String()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return httpResult{header: resp.Header, url: finalURL, notModified: true}, nil
+	}
+
+	data, err := o.readAllDecompressed(resp.Body, path, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		if errors.Is(err, ErrLoader) {
+			// a size limit or a decompression issue: no need to retry
+			return httpResult{}, err
+		}
+
+		return httpResult{}, transportError{err}
+	}
+
+	return httpResult{data: data, header: resp.Header, url: finalURL}, nil
+}
+
+// doHTTP sends a request for a remote document, and checks the response.
+//
+// The returned response has either the status 200, or 304 if a cached entry is provided.
+// On error, the response body is closed.
+func doHTTP(ctx context.Context, path string, o options, cached *CacheEntry) (*http.Response, error) {
+	resp, err := sendHTTP(ctx, path, o, cached, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && o.refreshesCredentials() && sameHost(resp.Request.URL, initialRequest(resp.Request).URL) {
+		// credentials may have expired: ask the provider to refresh them, and try again once
+		o.closeResponse(resp)
+
+		resp, err = sendHTTP(ctx, path, o, cached, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer o.closeResponse(resp)
+
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySnippet))
+
+		return nil, &LoadError{
+			Path:       path,
+			URL:        resp.Request.URL.String(),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       snippet,
+		}
+	}
+
+	if o.maxBytes > 0 && resp.ContentLength > o.maxBytes {
+		o.closeResponse(resp)
+
+		return nil, &MaxBytesError{Path: path, Limit: o.maxBytes}
+	}
+
+	return resp, nil
+}
+
+// sendHTTP sends a request for a remote document.
+func sendHTTP(ctx context.Context, path string, o options, cached *CacheEntry, refreshCredentials bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = o.checkHost(req.URL); err != nil {
+		return nil, err
+	}
+
+	if o.accept != "" {
+		req.Header.Set("Accept", o.accept)
+	}
+
+	for key, val := range o.customHeaders {
+		req.Header.Set(key, val)
+	}
+
+	creds, err := o.setCredentials(req, refreshCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil {
+		cached.setConditionalHeaders(req.Header)
+	}
+
+	client, err := o.httpClient(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		o.closeResponse(resp)
+
+		if errors.Is(err, ErrLoader) {
+			// a forbidden host, or too many redirects: no need to retry
+			return nil, err
+		}
+
+		return nil, transportError{&LoadError{Path: path, URL: path, Err: err}}
+	}
+
+	o.observeResponse(ctx, path, resp, sent)
+
+	return resp, nil
+}
+
+// httpClient returns the [http.Client] configured for a request.
+//
+// Redirects are checked against the host policy and the maximum number of redirects.
+// Whenever a request is redirected to another host, credentials and custom headers for all hosts are removed,
+// and credentials and custom headers scoped to the new host are set.
+//
+// The redirect policy of the configured client still applies.
+func (o options) httpClient(creds requestCredentials) (*http.Client, error) {
+	client := *o.client
+
+	if o.guardedClient != nil {
+		guarded, err := o.guardedClient()
+		if err != nil {
+			return nil, err
+		}
+
+		client = *guarded
+	}
+
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > o.maxRedirects {
+			return fmt.Errorf("stopped after %d redirects: %w", o.maxRedirects, ErrLoader)
+		}
+
+		if checkRedirect != nil {
+			if err := checkRedirect(req, via); err != nil {
+				return err
+			}
+		}
+
+		if err := o.checkHost(req.URL); err != nil {
+			return err
+		}
+
+		o.notify(req.Context(), onRedirect, Event{
+			Path:       via[0].URL.String(),
+			URL:        req.URL.String(),
+			StatusCode: req.Response.StatusCode,
+		})
+
+		if sameHost(req.URL, via[0].URL) {
+			return nil
+		}
+
+		for _, key := range slices.Concat(creds.global, creds.scoped) {
+			req.Header.Del(key)
+		}
+
+		for key := range o.customHeaders {
+			req.Header.Del(key)
+		}
+
+		if o.accept != "" && req.Header.Get("Accept") == "" {
+			req.Header.Set("Accept", o.accept)
+		}
+
+		_, _, err := o.setHostCredentials(req, false)
+
+		return err
+	}
+
+	return &client, nil
+}
@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// integrityFragment is the prefix of a URL fragment that holds the expected integrity of a document,
+// e.g. "https://example.com/pet.yaml#integrity=sha256-...".
+const integrityFragment = "#integrity="
+
+// integrityAlgorithms are the hash algorithms supported in integrity metadata, from the weakest to the strongest.
+var integrityAlgorithms = []struct {
+	name string
+	hash func() hash.Hash
+}{
+	{name: "sha256", hash: sha256.New},
+	{name: "sha384", hash: sha512.New384},
+	{name: "sha512", hash: sha512.New},
+}
+
+// Integrity computes the integrity metadata of a document, e.g. "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+// as expected by [WithIntegrity].
+func Integrity(data []byte) string {
+	return digest("sha256", sha256.New, data)
+}
+
+func digest(name string, newHash func() hash.Hash, data []byte) string {
+	h := newHash()
+	_, _ = h.Write(data)
+
+	return name + "-" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// splitIntegrity removes the integrity fragment from a path, if any.
+//
+// It returns the path, and the expected integrity: from the fragment, or else as set by [WithIntegrity].
+func (o options) splitIntegrity(pth string) (string, string) {
+	idx := strings.LastIndex(pth, integrityFragment)
+	if idx < 0 {
+		return pth, o.integrity
+	}
+
+	expected := pth[idx+len(integrityFragment):]
+	if unescaped, err := url.PathUnescape(expected); err == nil {
+		expected = unescaped
+	}
+
+	return pth[:idx], expected
+}
+
+// withIntegrity wraps a loader function, so that loaded documents are verified against their expected integrity.
+func (o options) withIntegrity(load func(string) ([]byte, error)) func(string) ([]byte, error) {
+	return func(p string) ([]byte, error) {
+		p, expected := o.splitIntegrity(p)
+
+		data, err := load(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = o.verifyIntegrity(p, data, expected); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	}
+}
+
+// verifyIntegrity checks a document against its expected integrity, and against the lockfile set with [WithLockfile].
+func (o options) verifyIntegrity(pth string, data []byte, expected string) error {
+	return o.verifyDigests(pth, func(i int) string {
+		algorithm := integrityAlgorithms[i]
+
+		return digest(algorithm.name, algorithm.hash, data)
+	}, expected)
+}
+
+// verifyDigests checks the digests of a document, computed by digestOf for an algorithm, against
+// its expected integrity, and against the lockfile set with [WithLockfile].
+func (o options) verifyDigests(pth string, digestOf func(int) string, expected string) error {
+	if expected != "" {
+		if err := checkIntegrity(pth, digestOf, expected); err != nil {
+			return err
+		}
+	}
+
+	if o.lockfile != nil {
+		return o.lockfile.verifyOrRecord(pth, digestOf)
+	}
+
+	return nil
+}
+
+// checkIntegrity checks the digests of a document against some integrity metadata, as defined by the
+// W3C subresource integrity specification: a space-separated list of digests like "sha256-{base64 digest}".
+//
+// Only the digests with the strongest algorithm are considered, and the document must match one of them.
+func checkIntegrity(pth string, digestOf func(int) string, expected string) error {
+	strongest := -1
+	var candidates []string
+
+	for _, metadata := range strings.Fields(expected) {
+		metadata, _, _ = strings.Cut(metadata, "?") // ignore options
+		name, _, _ := strings.Cut(metadata, "-")
+
+		for i, algorithm := range integrityAlgorithms {
+			if algorithm.name != name {
+				continue
+			}
+
+			switch {
+			case i > strongest:
+				strongest = i
+				candidates = []string{metadata}
+			case i == strongest:
+				candidates = append(candidates, metadata)
+			}
+		}
+	}
+
+	if strongest < 0 {
+		return fmt.Errorf("invalid integrity %q for %q: expected a sha256, sha384 or sha512 digest: %w", expected, pth, ErrLoader)
+	}
+
+	actual := digestOf(strongest)
+	for _, candidate := range candidates {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(actual)) == 1 {
+			return nil
+		}
+	}
+
+	return &IntegrityError{Path: pth, Expected: expected, Actual: actual}
+}
+
+// integrityReader verifies the integrity of a stream when it reaches its end.
+//
+// The digests are computed as the stream is read, with all supported algorithms.
+type integrityReader struct {
+	io.Reader
+
+	hashes []hash.Hash
+	verify func(digestOf func(int) string) error
+	err    error
+}
+
+func newIntegrityReader(rdr io.Reader, verify func(digestOf func(int) string) error) *integrityReader {
+	hashes := make([]hash.Hash, len(integrityAlgorithms))
+	for i, algorithm := range integrityAlgorithms {
+		hashes[i] = algorithm.hash()
+	}
+
+	return &integrityReader{
+		Reader: rdr,
+		hashes: hashes,
+		verify: verify,
+	}
+}
+
+func (r *integrityReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.Reader.Read(p)
+	for _, h := range r.hashes {
+		_, _ = h.Write(p[:n])
+	}
+
+	if errors.Is(err, io.EOF) {
+		r.err = err
+		if verifyErr := r.verify(r.digestOf); verifyErr != nil {
+			r.err = verifyErr
+
+			return n, verifyErr
+		}
+	}
+
+	return n, err
+}
+
+func (r *integrityReader) digestOf(i int) string {
+	return integrityAlgorithms[i].name + "-" + base64.StdEncoding.EncodeToString(r.hashes[i].Sum(nil))
+}
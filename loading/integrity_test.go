@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestIntegrity(t *testing.T) {
+	const localPath = "fixtures/petstore_fixture.json"
+
+	valid := Integrity(jsonPetStore)
+	invalid := Integrity([]byte("tampered"))
+
+	serv := httptest.NewServer(http.HandlerFunc(serveJSONPetStore))
+	t.Cleanup(serv.Close)
+
+	t.Run("should compute integrity metadata", func(t *testing.T) {
+		assert.EqualT(t, "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", Integrity(nil))
+	})
+
+	t.Run("should load a document matching its integrity", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(localPath, WithIntegrity(valid))
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+
+		doc, err := JSONDoc(serv.URL+"/pet.json", WithIntegrity(valid))
+		require.NoError(t, err)
+		assert.JSONEqBytes(t, jsonPetStore, doc)
+	})
+
+	t.Run("should fail on a document that does not match its integrity", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(serv.URL+"/pet.json", WithIntegrity(invalid))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+		assert.EqualT(t, serv.URL+"/pet.json", integrityErr.Path)
+		assert.EqualT(t, invalid, integrityErr.Expected)
+		assert.EqualT(t, valid, integrityErr.Actual)
+	})
+
+	t.Run("should read the integrity from a URL fragment", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(localPath + "#integrity=" + url.PathEscape(valid))
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+
+		// the fragment takes precedence over the option
+		_, err = LoadFromFileOrHTTP(serv.URL+"/pet.json#integrity="+invalid, WithIntegrity(valid))
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should verify documents with any format", func(t *testing.T) {
+		doc, err := Doc(serv.URL + "/pet.json#integrity=" + valid)
+		require.NoError(t, err)
+		assert.JSONEqBytes(t, jsonPetStore, doc)
+
+		_, err = YAMLDoc("fixtures/petstore_fixture.yaml", WithIntegrity(invalid))
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+
+		_, err = Doc(localPath, WithIntegrity(invalid))
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should verify documents loaded by a BatchLoader", func(t *testing.T) {
+		loader := NewBatchLoader()
+
+		docs, err := loader.LoadMany(context.Background(), []string{
+			serv.URL + "/pet.json#integrity=" + valid,
+			serv.URL + "/pet.json#integrity=" + invalid,
+		})
+		require.Error(t, err)
+		assert.Equal(t, jsonPetStore, docs[0])
+		assert.Nil(t, docs[1])
+
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should verify a streamed document at the end of the stream", func(t *testing.T) {
+		rdr, _, err := Open(localPath, WithIntegrity(valid))
+		require.NoError(t, err)
+		b, err := io.ReadAll(rdr)
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+		require.NoError(t, rdr.Close())
+
+		rdr, _, err = Open(serv.URL+"/pet.json#integrity="+invalid, WithHTTPClient(serv.Client()))
+		require.NoError(t, err)
+		_, err = io.ReadAll(rdr)
+		require.NoError(t, rdr.Close())
+
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should use the strongest algorithm", func(t *testing.T) {
+		h := sha512.Sum512(jsonPetStore)
+		sha512Digest := "sha512-" + base64.StdEncoding.EncodeToString(h[:])
+
+		_, err := LoadFromFileOrHTTP(localPath, WithIntegrity(invalid+" "+sha512Digest+"?opt"))
+		require.NoError(t, err)
+
+		_, err = LoadFromFileOrHTTP(localPath, WithIntegrity(valid+" sha512-invalid"))
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should fail on invalid integrity metadata", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(localPath, WithIntegrity("md5-abc"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.StringContainsT(t, err.Error(), "invalid integrity")
+	})
+}
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// interpolator expands the placeholders in the string values of a document.
+type interpolator struct {
+	lookup  func(string) (string, bool)
+	missing []MissingVariable
+}
+
+// expand replaces the placeholders in a string value, located by its reference tokens.
+//
+// Placeholders with an undefined variable are left unchanged, and recorded as missing.
+func (ip *interpolator) expand(value string, tokens []string) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+
+	var b strings.Builder
+	for {
+		idx := strings.IndexByte(value, '$')
+		if idx < 0 || idx == len(value)-1 {
+			b.WriteString(value)
+
+			return b.String()
+		}
+
+		b.WriteString(value[:idx])
+		value = value[idx:]
+
+		switch value[1] {
+		case '$':
+			// escaped dollar sign
+			b.WriteByte('$')
+			value = value[2:]
+
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			value = value[1:]
+
+			continue
+		}
+
+		end := strings.IndexByte(value, '}')
+		if end < 0 {
+			// not a placeholder
+			b.WriteString(value)
+
+			return b.String()
+		}
+
+		placeholder := value[:end+1]
+		value = value[end+1:]
+
+		name, fallback, hasDefault := strings.Cut(placeholder[2:end], ":-")
+		resolved, isDefined := ip.lookup(name)
+
+		switch {
+		case hasDefault && resolved == "":
+			b.WriteString(fallback)
+		case isDefined:
+			b.WriteString(resolved)
+		default:
+			b.WriteString(placeholder)
+			ip.missing = append(ip.missing, MissingVariable{Name: name, Pointer: formatPointer(tokens)})
+		}
+	}
+}
+
+// err reports the missing variables, if any.
+func (ip *interpolator) err(pth string) error {
+	if len(ip.missing) == 0 {
+		return nil
+	}
+
+	return &InterpolationError{Path: pth, Missing: ip.missing}
+}
+
+// interpolateJSON expands the placeholders in the string values of a JSON document.
+//
+// Keys, numbers and the order of keys are preserved. The document is compacted.
+func (o options) interpolateJSON(pth string, data []byte) ([]byte, error) {
+	if o.interpolation == nil {
+		return data, nil
+	}
+
+	ip := &interpolator{lookup: o.interpolation}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var (
+		out    bytes.Buffer
+		frames []jsonFrame
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON document at %q: %w: %w", pth, err, ErrLoader)
+		}
+
+		if delim, isDelim := token.(json.Delim); isDelim && (delim == '}' || delim == ']') {
+			out.WriteRune(rune(delim))
+			frames = frames[:len(frames)-1]
+			endValue(frames)
+
+			continue
+		}
+
+		if len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			if top.count > 0 && (!top.isObject || top.expectKey) {
+				out.WriteByte(',')
+			}
+
+			if top.isObject && top.expectKey {
+				key, _ := token.(string)
+				writeJSONString(&out, key)
+				out.WriteByte(':')
+				top.token = key
+				top.expectKey = false
+
+				continue
+			}
+
+			if !top.isObject {
+				top.token = strconv.Itoa(top.count)
+			}
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			out.WriteRune(rune(value))
+			frames = append(frames, jsonFrame{isObject: value == '{', expectKey: value == '{'})
+
+			continue
+		case string:
+			writeJSONString(&out, ip.expand(value, frameTokens(frames)))
+		case json.Number:
+			out.WriteString(value.String())
+		case bool:
+			out.WriteString(strconv.FormatBool(value))
+		case nil:
+			out.WriteString("null")
+		}
+
+		endValue(frames)
+	}
+
+	if err := ip.err(pth); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// jsonFrame is an object or array being rewritten by interpolateJSON.
+type jsonFrame struct {
+	isObject  bool
+	expectKey bool
+	count     int
+	token     string // the key or index of the current member
+}
+
+func endValue(frames []jsonFrame) {
+	if len(frames) == 0 {
+		return
+	}
+
+	top := &frames[len(frames)-1]
+	top.count++
+	top.expectKey = top.isObject
+}
+
+func frameTokens(frames []jsonFrame) []string {
+	tokens := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		tokens = append(tokens, frame.token)
+	}
+
+	return tokens
+}
+
+func writeJSONString(out *bytes.Buffer, value string) {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)   // a string is always encoded
+	out.Truncate(out.Len() - 1) // remove the trailing newline
+}
+
+// interpolateYAML expands the placeholders in the string values of a YAML document.
+func (o options) interpolateYAML(pth string, doc any) error {
+	if o.interpolation == nil {
+		return nil
+	}
+
+	node, isNode := doc.(*yaml.Node)
+	if !isNode {
+		return nil
+	}
+
+	ip := &interpolator{lookup: o.interpolation}
+	interpolateYAMLNode(ip, node, nil)
+
+	return ip.err(pth)
+}
+
+func interpolateYAMLNode(ip *interpolator, node *yaml.Node, tokens []string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			interpolateYAMLNode(ip, child, tokens)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			interpolateYAMLNode(ip, node.Content[i+1], append(tokens[:len(tokens):len(tokens)], node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			interpolateYAMLNode(ip, child, append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i)))
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!str" {
+			node.Value = ip.expand(node.Value, tokens)
+		}
+	}
+}
+
+// formatPointer builds a JSON Pointer (RFC 6901) from its reference tokens.
+func formatPointer(tokens []string) string {
+	var b strings.Builder
+
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+
+	return b.String()
+}
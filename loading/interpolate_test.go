@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/swag/yamlutils"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestWithInterpolation(t *testing.T) {
+	env := map[string]string{
+		"SERVICE_HOST": "api.example.com",
+		"PORT":         "8080",
+		"EMPTY":        "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	}
+
+	fsys := fstest.MapFS{
+		"spec.json": &fstest.MapFile{Data: []byte(`{
+  "host": "${SERVICE_HOST}:${PORT}",
+  "${SERVICE_HOST}": "keys are not expanded",
+  "big": 12345678901234567890,
+  "servers": [{"url": "https://${SERVICE_HOST}/<v1>"}, {"url": "${MISSING:-localhost}"}],
+  "price": "$5 or $$10, $${PORT}, ${EMPTY:-fallback}, ${EMPTY}",
+  "enabled": true,
+  "nothing": null
+}`)},
+		"spec.yaml": &fstest.MapFile{Data: []byte(`host: ${SERVICE_HOST}
+port: ${PORT}
+count: 3
+servers:
+  - url: https://${SERVICE_HOST}
+`)},
+		"missing.yaml": &fstest.MapFile{Data: []byte(`info:
+  title: ${TITLE}
+servers:
+  - url: https://${HOST}:${PORT}
+  - url: https://${HOST}
+"a/b": ${TITLE}
+`)},
+		"missing.json":    &fstest.MapFile{Data: []byte(`{"servers": [{"url": "${HOST}"}], "unterminated": "${HOST"}`)},
+		"stream.yaml":     &fstest.MapFile{Data: []byte("host: ${SERVICE_HOST}\n---\nhost: ${HOST}\n")},
+		"placeholder.txt": &fstest.MapFile{Data: []byte("${SERVICE_HOST}")},
+	}
+
+	t.Run("should expand placeholders in a JSON document", func(t *testing.T) {
+		doc, err := JSONDoc("spec.json", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{
+  "host": "api.example.com:8080",
+  "${SERVICE_HOST}": "keys are not expanded",
+  "big": 12345678901234567890,
+  "servers": [{"url": "https://api.example.com/<v1>"}, {"url": "localhost"}],
+  "price": "$5 or $10, ${PORT}, fallback, ",
+  "enabled": true,
+  "nothing": null
+}`, string(doc))
+		assert.StringContainsT(t, string(doc), `"big":12345678901234567890`)
+		assert.StringContainsT(t, string(doc), `<v1>`)
+
+		same, err := Doc("spec.json", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		assert.Equal(t, doc, same)
+	})
+
+	t.Run("should expand placeholders in a YAML document", func(t *testing.T) {
+		expected := `{"host": "api.example.com", "port": "8080", "count": 3, "servers": [{"url": "https://api.example.com"}]}`
+
+		doc, err := YAMLDoc("spec.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		assert.JSONEqT(t, expected, string(doc))
+
+		doc, err = Doc("spec.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		assert.JSONEqT(t, expected, string(doc))
+
+		data, err := YAMLData("spec.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		doc, err = yamlutils.YAMLToJSON(data)
+		require.NoError(t, err)
+		assert.JSONEqT(t, expected, string(doc))
+	})
+
+	t.Run("should not expand placeholders by default", func(t *testing.T) {
+		doc, err := YAMLDoc("spec.yaml", WithFS(fsys))
+		require.NoError(t, err)
+		assert.StringContainsT(t, string(doc), "${SERVICE_HOST}")
+	})
+
+	t.Run("should never expand raw bytes", func(t *testing.T) {
+		data, err := LoadFromFileOrHTTP("placeholder.txt", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		assert.EqualT(t, "${SERVICE_HOST}", string(data))
+	})
+
+	t.Run("should list all missing variables", func(t *testing.T) {
+		_, err := YAMLDoc("missing.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+
+		var interpolationErr *InterpolationError
+		require.ErrorAs(t, err, &interpolationErr)
+		assert.EqualT(t, "missing.yaml", interpolationErr.Path)
+		assert.Equal(t, []MissingVariable{
+			{Name: "TITLE", Pointer: "/info/title"},
+			{Name: "HOST", Pointer: "/servers/0/url"},
+			{Name: "HOST", Pointer: "/servers/1/url"},
+			{Name: "TITLE", Pointer: "/a~1b"},
+		}, interpolationErr.Missing)
+		assert.StringContainsT(t, err.Error(), `TITLE at "/info/title"`)
+
+		_, err = JSONDoc("missing.json", WithFS(fsys), WithInterpolation(lookup))
+		require.ErrorAs(t, err, &interpolationErr)
+		assert.Equal(t, []MissingVariable{
+			{Name: "HOST", Pointer: "/servers/0/url"},
+		}, interpolationErr.Missing)
+	})
+
+	t.Run("should expand placeholders in a YAML stream", func(t *testing.T) {
+		_, err := YAMLDocs("stream.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.Error(t, err)
+		assert.StringContainsT(t, err.Error(), "index 1")
+
+		var interpolationErr *InterpolationError
+		require.ErrorAs(t, err, &interpolationErr)
+
+		env["HOST"] = "other.example.com"
+		t.Cleanup(func() { delete(env, "HOST") })
+
+		docs, err := YAMLDocs("stream.yaml", WithFS(fsys), WithInterpolation(lookup))
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.JSONEqT(t, `{"host": "api.example.com"}`, string(docs[0]))
+		assert.JSONEqT(t, `{"host": "other.example.com"}`, string(docs[1]))
+	})
+}
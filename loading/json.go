@@ -4,22 +4,76 @@
 package loading
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"path/filepath"
+
+	"github.com/go-openapi/swag/jsonutils"
 )
 
 // JSONMatcher matches json for a file loader.
+//
+// A compression extension such as ".gz" is ignored, e.g. "spec.json.gz" is matched.
+//
+// JSONC and JSON5 documents, with a ".jsonc" or ".json5" extension, are matched too.
 func JSONMatcher(path string) bool {
-	ext := filepath.Ext(path)
-	return ext == ".json" || ext == ".jsn" || ext == ".jso"
+	ext := filepath.Ext(trimCompressionExt(path))
+	return ext == ".json" || ext == ".jsn" || ext == ".jso" || isRelaxedJSONExt(ext)
 }
 
 // JSONDoc loads a json document from either a file or a remote url.
 func JSONDoc(path string, opts ...Option) (json.RawMessage, error) {
-	data, err := LoadFromFileOrHTTP(path, opts...)
+	return JSONDocContext(context.Background(), path, opts...)
+}
+
+// JSONDocContext loads a json document from either a file or a remote url, with a context.
+//
+// A JSONC or JSON5 document, with a ".jsonc" or ".json5" extension, is converted to strict JSON:
+// comments and trailing commas are removed, single-quoted strings and unquoted keys are enclosed in double quotes.
+//
+// See [LoadFromFileOrHTTPContext].
+func JSONDocContext(ctx context.Context, path string, opts ...Option) (json.RawMessage, error) {
+	o := optionsWithDefaults(opts)
+
+	data, err := strategyLoader{options: o}.Load(ctx, path)
 	if err != nil {
 		return nil, errors.Join(err, ErrLoader)
 	}
+
+	if data, err = toUTF8(path, data); err != nil {
+		return nil, err
+	}
+
+	if data, err = relaxJSON(path, data); err != nil {
+		return nil, err
+	}
+
+	data, err = o.interpolateJSON(path, data)
+	if err != nil {
+		return nil, err
+	}
+
 	return json.RawMessage(data), nil
 }
+
+func isRelaxedJSONExt(ext string) bool {
+	return ext == ".jsonc" || ext == ".json5"
+}
+
+// relaxJSON converts a JSONC or JSON5 document, designated by its extension, to strict JSON.
+//
+// Other documents are returned unchanged.
+func relaxJSON(path string, data []byte) ([]byte, error) {
+	if !isRelaxedJSONExt(filepath.Ext(trimCompressionExt(documentPath(path)))) {
+		return data, nil
+	}
+
+	relaxed, err := jsonutils.RelaxedToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON document at %q: %w: %w", path, err, ErrLoader)
+	}
+
+	return relaxed, nil
+}
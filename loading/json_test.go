@@ -4,10 +4,13 @@
 package loading
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"testing/fstest"
 
+	"github.com/go-openapi/swag/jsonutils"
 	"github.com/go-openapi/testify/v2/assert"
 	"github.com/go-openapi/testify/v2/require"
 )
@@ -17,6 +20,8 @@ func TestJSONMatcher(t *testing.T) {
 		assert.TrueT(t, JSONMatcher("local.json"))
 		assert.TrueT(t, JSONMatcher("local.jso"))
 		assert.TrueT(t, JSONMatcher("local.jsn"))
+		assert.TrueT(t, JSONMatcher("local.jsonc"))
+		assert.TrueT(t, JSONMatcher("local.json5"))
 		assert.FalseT(t, JSONMatcher("local.yml"))
 	})
 }
@@ -43,4 +48,93 @@ func TestJSONDoc(t *testing.T) {
 		_, err := JSONDoc(ts.URL)
 		require.Error(t, err)
 	})
+
+	t.Run("with JSONC and JSON5 documents", func(t *testing.T) {
+		const relaxed = `{
+  // comment
+  name: 'pet', /* inline */
+  tags: ['a', 'b',],
+}`
+		fsys := fstest.MapFS{
+			"config.jsonc":  &fstest.MapFile{Data: []byte(relaxed)},
+			"config.json5":  &fstest.MapFile{Data: []byte(relaxed)},
+			"config.json":   &fstest.MapFile{Data: []byte(relaxed)},
+			"broken.jsonc":  &fstest.MapFile{Data: []byte(`{name: 'pet}`)},
+			"ordered.json5": &fstest.MapFile{Data: []byte(`{z: 1, a: 2, m: 3,}`)},
+		}
+
+		t.Run("should convert to strict JSON", func(t *testing.T) {
+			for _, name := range []string{"config.jsonc", "config.json5"} {
+				doc, err := JSONDoc(name, WithFS(fsys))
+				require.NoError(t, err)
+				assert.JSONEqT(t, `{"name":"pet","tags":["a","b"]}`, string(doc))
+
+				doc, err = Doc(name, WithFS(fsys))
+				require.NoError(t, err)
+				assert.JSONEqT(t, `{"name":"pet","tags":["a","b"]}`, string(doc))
+			}
+		})
+
+		t.Run("should convert a remote document to strict JSON", func(t *testing.T) {
+			serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.Header().Set("Content-Type", "text/plain")
+				_, _ = rw.Write([]byte(relaxed))
+			}))
+			t.Cleanup(serv.Close)
+
+			doc, err := Doc(serv.URL + "/config.jsonc?version=1")
+			require.NoError(t, err)
+			assert.JSONEqT(t, `{"name":"pet","tags":["a","b"]}`, string(doc))
+		})
+
+		t.Run("should preserve the order of keys", func(t *testing.T) {
+			doc, err := JSONDoc("ordered.json5", WithFS(fsys))
+			require.NoError(t, err)
+
+			var value jsonutils.JSONMapSlice
+			require.NoError(t, jsonutils.ReadJSON(doc, &value))
+			keys := make([]string, 0, len(value))
+			for _, item := range value {
+				keys = append(keys, item.Key)
+			}
+			assert.Equal(t, []string{"z", "a", "m"}, keys)
+		})
+
+		t.Run("should not relax a JSON document", func(t *testing.T) {
+			_, err := Doc("config.json", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should fail on an invalid document", func(t *testing.T) {
+			_, err := JSONDoc("broken.jsonc", WithFS(fsys))
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			require.ErrorIs(t, err, jsonutils.ErrJSON)
+		})
+	})
+}
+
+func TestJSONDocContext(t *testing.T) {
+	t.Run("should retrieve pet store API as JSON", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveJSONPetStore))
+		defer serv.Close()
+
+		s, err := JSONDocContext(context.Background(), serv.URL)
+		require.NoError(t, err)
+		require.JSONEqBytes(t, jsonPetStore, s)
+	})
+
+	t.Run("should not retrieve any doc with a cancelled context", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveJSONPetStore))
+		defer serv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := JSONDocContext(ctx, serv.URL)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, ErrLoader)
+	})
 }
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// Loader loads documents designated by a path or URI.
+//
+// Loaders may be assembled with [FirstOf], [WithPrefix] and [Cached], e.g. to look for documents in
+// embedded defaults, then in local overrides, then remotely:
+//
+//	loader := loading.FirstOf(
+//		loading.ReadOnlyFS(defaults),
+//		loading.ReadOnlyFS(os.DirFS("overrides")),
+//		loading.NewLoader(),
+//	)
+//
+// A [Loader] reports a missing document with an error for which [IsNotFound] is true.
+type Loader interface {
+	Load(ctx context.Context, uri string) ([]byte, error)
+}
+
+var (
+	_ Loader = LoaderFunc(nil)
+	_ Loader = strategyLoader{}
+	_ Loader = &BatchLoader{}
+)
+
+// LoaderFunc adapts a function to a [Loader].
+type LoaderFunc func(ctx context.Context, uri string) ([]byte, error)
+
+// Load calls f(ctx, uri).
+func (f LoaderFunc) Load(ctx context.Context, uri string) ([]byte, error) {
+	return f(ctx, uri)
+}
+
+// NewLoader builds the [Loader] used by [LoadFromFileOrHTTP], with some options which apply to all loads.
+//
+// The document is loaded from a local file, a remote url or any other supported scheme,
+// as explained by [LoadStrategy].
+//
+// Archives are retained by the [Loader], so that several entries are loaded from an archive
+// fetched or read only once (see [WithArchiveCache]).
+func NewLoader(opts ...Option) Loader {
+	return strategyLoader{options: optionsWithDefaults(opts).withArchiveCache()}
+}
+
+// ReadOnlyFS builds a [Loader] for the documents in a file system.
+//
+// Paths are resolved against the file system, like with [WithFS].
+// URIs with a scheme other than "file" are reported as not found.
+func ReadOnlyFS(fsys fs.FS, opts ...Option) Loader {
+	o := optionsWithDefaults(append(opts, WithFS(fsys)))
+
+	return LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		if kind, _ := o.strategyFor(uri); kind != strategyLocal {
+			return nil, notFoundError(uri)
+		}
+
+		return strategyLoader{options: o}.Load(ctx, uri)
+	})
+}
+
+// FirstOf builds a [Loader] which tries several loaders in turn, and returns the first document found.
+//
+// The next loader is tried only if the document is not found (see [IsNotFound]): any other error is returned
+// immediately. If no loader finds the document, the returned error joins all errors.
+func FirstOf(loaders ...Loader) Loader {
+	return LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		if len(loaders) == 0 {
+			return nil, notFoundError(uri)
+		}
+
+		errs := make([]error, 0, len(loaders))
+		for _, loader := range loaders {
+			data, err := loader.Load(ctx, uri)
+			if err == nil {
+				return data, nil
+			}
+
+			if !IsNotFound(err) {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+		}
+
+		return nil, errors.Join(errs...)
+	})
+}
+
+// WithPrefix builds a [Loader] for the paths or URIs starting with a prefix.
+//
+// The prefix is removed before calling the loader. Other paths or URIs are reported as not found.
+//
+// For example, WithPrefix("https://example.com/specs/", ReadOnlyFS(specs)) serves the documents
+// under this URL from a local copy.
+func WithPrefix(prefix string, loader Loader) Loader {
+	return LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		rest, hasPrefix := strings.CutPrefix(uri, prefix)
+		if !hasPrefix {
+			return nil, notFoundError(uri)
+		}
+
+		return loader.Load(ctx, rest)
+	})
+}
+
+// Cached builds a [Loader] which retains the documents loaded by another loader, keyed by path or URI.
+//
+// Documents are stored as the body of a [CacheEntry], and never expire. Errors are not cached.
+// If cache is nil, a [MemoryCache] is used.
+func Cached(loader Loader, cache Cache) Loader {
+	if cache == nil {
+		cache = NewMemoryCache()
+	}
+
+	return LoaderFunc(func(ctx context.Context, uri string) ([]byte, error) {
+		if entry, found := cache.Get(uri); found {
+			return bytes.Clone(entry.Body), nil
+		}
+
+		data, err := loader.Load(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = cache.Put(uri, CacheEntry{Body: bytes.Clone(data)}); err != nil {
+			return nil, fmt.Errorf("could not cache document at %q: %w: %w", uri, err, ErrLoader)
+		}
+
+		return data, nil
+	})
+}
+
+// IsNotFound tells if an error reports a missing document: either a missing file ([fs.ErrNotExist]),
+// or a remote document with status 404 or 410.
+func IsNotFound(err error) bool {
+	if errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		return false
+	}
+
+	return loadErr.StatusCode == http.StatusNotFound || loadErr.StatusCode == http.StatusGone
+}
+
+func notFoundError(uri string) error {
+	return &LoadError{Path: uri, Err: fs.ErrNotExist}
+}
+
+// strategyLoader loads documents with the strategy described by [LoadStrategy].
+type strategyLoader struct {
+	options options
+
+	// strategyPath, if set, determines the strategy instead of the loaded path (see [LoadStrategy])
+	strategyPath string
+
+	// local and remote override the default loaders, if set
+	local  func(string) ([]byte, error)
+	remote func(string) ([]byte, error)
+}
+
+// Load loads a document, and verifies its integrity.
+func (l strategyLoader) Load(ctx context.Context, uri string) ([]byte, error) {
+	return observeLoad(ctx, l.options, uri, func() ([]byte, error) {
+		return l.options.withIntegrity(func(p string) ([]byte, error) {
+			return l.load(ctx, p)
+		})(uri)
+	})
+}
+
+// load carries out the strategy, without verifying integrity nor notifying hooks.
+func (l strategyLoader) load(ctx context.Context, uri string) ([]byte, error) {
+	o := l.options
+
+	local := l.local
+	if local == nil {
+		local = o.readFileContextFunc(ctx)
+	}
+
+	remote := l.remote
+	if remote == nil {
+		remote = loadHTTPBytes(ctx, o)
+	}
+
+	pth := l.strategyPath
+	if pth == "" {
+		pth = uri
+	}
+
+	return loadStrategy(ctx, pth, local, remote, o)(uri)
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestLoaderCombinators(t *testing.T) {
+	ctx := context.Background()
+	defaults := fstest.MapFS{
+		"pet.json":   {Data: []byte(`{"name":"default"}`)},
+		"store.json": {Data: []byte(`{"store":"default"}`)},
+	}
+	overrides := fstest.MapFS{
+		"pet.json": {Data: []byte(`{"name":"override"}`)},
+	}
+
+	t.Run("with ReadOnlyFS", func(t *testing.T) {
+		t.Run("should load a document from the file system", func(t *testing.T) {
+			data, err := ReadOnlyFS(defaults).Load(ctx, "pet.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"name":"default"}`), data)
+		})
+
+		t.Run("should report a missing document as not found", func(t *testing.T) {
+			_, err := ReadOnlyFS(defaults).Load(ctx, "missing.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should report a remote URL as not found", func(t *testing.T) {
+			_, err := ReadOnlyFS(defaults).Load(ctx, "https://example.com/pet.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+		})
+	})
+
+	t.Run("with FirstOf", func(t *testing.T) {
+		loader := FirstOf(ReadOnlyFS(overrides), ReadOnlyFS(defaults))
+
+		t.Run("should load from the first loader that finds the document", func(t *testing.T) {
+			data, err := loader.Load(ctx, "pet.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"name":"override"}`), data)
+
+			data, err = loader.Load(ctx, "store.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"store":"default"}`), data)
+		})
+
+		t.Run("should report a document found nowhere as not found", func(t *testing.T) {
+			_, err := loader.Load(ctx, "missing.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+		})
+
+		t.Run("should fall through on a remote document not found", func(t *testing.T) {
+			serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/pet.json" {
+					http.NotFound(rw, r)
+
+					return
+				}
+
+				serveJSONPetStore(rw, r)
+			}))
+			t.Cleanup(serv.Close)
+
+			remoteFirst := FirstOf(NewLoader(), WithPrefix(serv.URL+"/", ReadOnlyFS(defaults)))
+
+			data, err := remoteFirst.Load(ctx, serv.URL+"/pet.json")
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, data)
+
+			data, err = remoteFirst.Load(ctx, serv.URL+"/store.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"store":"default"}`), data)
+		})
+
+		t.Run("should stop on other errors", func(t *testing.T) {
+			errBroken := errors.New("broken")
+			calls := 0
+			broken := FirstOf(
+				LoaderFunc(func(context.Context, string) ([]byte, error) { return nil, errBroken }),
+				LoaderFunc(func(context.Context, string) ([]byte, error) {
+					calls++
+
+					return []byte(`{}`), nil
+				}),
+			)
+
+			_, err := broken.Load(ctx, "pet.json")
+			require.ErrorIs(t, err, errBroken)
+			assert.EqualT(t, 0, calls)
+		})
+
+		t.Run("should report not found without loaders", func(t *testing.T) {
+			_, err := FirstOf().Load(ctx, "pet.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+		})
+	})
+
+	t.Run("with WithPrefix", func(t *testing.T) {
+		loader := WithPrefix("https://example.com/specs/", ReadOnlyFS(defaults))
+
+		t.Run("should strip the prefix", func(t *testing.T) {
+			data, err := loader.Load(ctx, "https://example.com/specs/pet.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"name":"default"}`), data)
+		})
+
+		t.Run("should report other URIs as not found", func(t *testing.T) {
+			_, err := loader.Load(ctx, "https://example.org/specs/pet.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+		})
+	})
+
+	t.Run("with Cached", func(t *testing.T) {
+		t.Run("should load a document only once", func(t *testing.T) {
+			calls := 0
+			loader := Cached(LoaderFunc(func(context.Context, string) ([]byte, error) {
+				calls++
+
+				return []byte(`{"name":"cached"}`), nil
+			}), nil)
+
+			for range 3 {
+				data, err := loader.Load(ctx, "pet.json")
+				require.NoError(t, err)
+				assert.JSONEqBytes(t, []byte(`{"name":"cached"}`), data)
+
+				data[0] = 'x' // callers get their own copy
+			}
+			assert.EqualT(t, 1, calls)
+		})
+
+		t.Run("should not cache errors", func(t *testing.T) {
+			cache := NewMemoryCache()
+			loader := Cached(ReadOnlyFS(defaults), cache)
+
+			_, err := loader.Load(ctx, "missing.json")
+			require.Error(t, err)
+
+			_, found := cache.Get("missing.json")
+			assert.FalseT(t, found)
+		})
+	})
+
+	t.Run("with NewLoader", func(t *testing.T) {
+		t.Run("should load like LoadFromFileOrHTTP", func(t *testing.T) {
+			data, err := NewLoader(WithFS(defaults)).Load(ctx, "pet.json")
+			require.NoError(t, err)
+			assert.JSONEqBytes(t, []byte(`{"name":"default"}`), data)
+		})
+
+		t.Run("should report a missing file as not found", func(t *testing.T) {
+			_, err := NewLoader().Load(ctx, "fixtures/missing.json")
+			require.Error(t, err)
+			require.TrueT(t, IsNotFound(err))
+			require.ErrorIs(t, err, fs.ErrNotExist)
+		})
+	})
+}
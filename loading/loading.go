@@ -6,10 +6,9 @@ package loading
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"io/fs"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -19,16 +18,44 @@ import (
 
 // LoadFromFileOrHTTP loads the bytes from a file or a remote http server based on the path passed in
 func LoadFromFileOrHTTP(pth string, opts ...Option) ([]byte, error) {
-	o := optionsWithDefaults(opts)
-	return LoadStrategy(pth, o.ReadFileFunc(), loadHTTPBytes(opts...), opts...)(pth)
+	return LoadFromFileOrHTTPContext(context.Background(), pth, opts...)
+}
+
+// LoadFromFileOrHTTPContext loads the bytes from a file or a remote http server based on the path passed in.
+//
+// It is a shorthand for [NewLoader] followed by [Loader.Load].
+//
+// The provided context governs the whole loading operation: cancelling it aborts an ongoing
+// HTTP request as well as a local read.
+//
+// The timeout set by [WithTimeout] applies on top of any deadline already carried by the context:
+// whichever comes first wins.
+func LoadFromFileOrHTTPContext(ctx context.Context, pth string, opts ...Option) ([]byte, error) {
+	return NewLoader(opts...).Load(ctx, pth)
 }
 
 // LoadStrategy returns a loader function for a given path or URI.
 //
-// The load strategy returns the remote load for any path starting with `http`.
-// So this works for any URI with a scheme `http` or `https`.
+// The load strategy is determined by the scheme of the URI:
+//   - loaders registered with [WithSchemeLoader] take precedence
+//   - the remote loader is used for the `http` and `https` schemes
+//   - a `data:` URI (RFC 2397) is decoded inline
+//   - the local loader is used for the `file` scheme
+//   - any other scheme is rejected with an error
+//
+// A path like `bundle.zip!/specs/pet.yaml` designates an entry in a zip or tar archive,
+// which may itself be a local or a remote document. See also [OpenArchive].
+//
+// The fallback strategy, when the path has no scheme, is to call the local loader.
+//
+// URL rewrites set with [WithURLRewrites] apply first, before the strategy is determined.
 //
-// The fallback strategy is to call the local loader.
+// A fragment like `#integrity=sha256-...` is removed from the path, and the loaded document is verified
+// against this integrity (see [WithIntegrity]).
+//
+// Notice that single-letter schemes are not considered schemes, but windows drive letters.
+//
+// See also [NewLoader] and [FirstOf], to look for documents in several places.
 //
 // The local loader takes a local file system path (absolute or relative) as argument,
 // or alternatively a `file://...` URI, **without host** (see also below for windows).
@@ -54,107 +81,209 @@ func LoadFromFileOrHTTP(pth string, opts ...Option) ([]byte, error) {
 // - `file:///c:/folder/file` becomes `C:\folder\file`
 // - `file://c:/folder/file` is tolerated (without leading `/`) and becomes `c:\folder\file`
 func LoadStrategy(pth string, local, remote func(string) ([]byte, error), opts ...Option) func(string) ([]byte, error) {
-	if strings.HasPrefix(pth, "http") {
-		return remote
+	loader := strategyLoader{
+		options:      optionsWithDefaults(opts),
+		strategyPath: pth,
+		local:        local,
+		remote:       remote,
 	}
-	o := optionsWithDefaults(opts)
-	_, isEmbedFS := o.fs.(embed.FS)
 
 	return func(p string) ([]byte, error) {
-		upth, err := url.PathUnescape(p)
-		if err != nil {
-			return nil, err
+		return loader.Load(context.Background(), p)
+	}
+}
+
+func loadStrategy(ctx context.Context, pth string, local, remote func(string) ([]byte, error), o options) func(string) ([]byte, error) {
+	if len(o.rewrites) > 0 || o.strictRewrites {
+		return func(p string) ([]byte, error) {
+			target, rewritten, err := o.rewriteURL(p)
+			if err != nil {
+				return nil, err
+			}
+
+			if rewritten.rewrittenFS {
+				local = rewritten.readFileContextFunc(ctx)
+			}
+
+			return loadStrategy(ctx, target, local, remote, rewritten)(target)
 		}
+	}
+
+	kind, scheme := o.strategyFor(pth)
 
-		cpth, hasPrefix := strings.CutPrefix(upth, "file://")
-		if !hasPrefix || isEmbedFS || runtime.GOOS != "windows" {
-			// crude processing: trim the file:// prefix. This leaves full URIs with a host with a (mostly) unexpected result
-			// regular file path provided: just normalize slashes
-			if isEmbedFS {
-				// on windows, we need to slash the path if FS is an embed FS.
-				return local(strings.TrimLeft(filepath.ToSlash(cpth), "./")) // remove invalid leading characters for embed FS
+	switch kind {
+	case strategyRegistered:
+		loader := o.schemes[scheme]
+
+		return func(p string) ([]byte, error) {
+			data, err := loader(ctx, p)
+			if err != nil {
+				return nil, err
 			}
 
-			return local(filepath.FromSlash(cpth))
+			return o.decompressBytes(data, p)
 		}
+	case strategyRemote:
+		return func(p string) ([]byte, error) {
+			data, err := remote(p)
+			if err != nil {
+				return nil, err
+			}
 
-		// windows-only pre-processing of file://... URIs, excluding embed.FS
+			// the limit applies to remote loaders supplied by the caller, too
+			return checkMaxBytes(data, p, o.maxBytes)
+		}
+	case strategyArchive:
+		return func(p string) ([]byte, error) {
+			return o.loadFromArchive(ctx, p, func(archive string) ([]byte, error) {
+				data, err := loadStrategy(ctx, archive, local, remote, o)(archive)
+				if err != nil {
+					return nil, err
+				}
 
-		// support for canonical file URIs on windows.
-		u, err := url.Parse(filepath.ToSlash(upth))
-		if err != nil {
-			return nil, err
+				return o.decompressBytes(data, archive)
+			})
 		}
+	case strategyData:
+		return func(p string) ([]byte, error) {
+			data, err := loadDataURI(p)
+			if err != nil {
+				return nil, err
+			}
 
-		if u.Host != "" {
-			// assume UNC name (volume share)
-			// NOTE: UNC port not yet supported
-
-			// when the "host" segment is a drive letter:
-			// file://C:/folder/... => C:\folder
-			upth = path.Clean(strings.Join([]string{u.Host, u.Path}, `/`))
-			if !strings.HasSuffix(u.Host, ":") && u.Host[0] != '.' {
-				// tolerance: if we have a leading dot, this can't be a host
-				// file://host/share/folder\... ==> \\host\share\path\folder
-				upth = "//" + upth
+			return o.decompressBytes(data, p)
+		}
+	case strategyUnsupported:
+		return func(string) ([]byte, error) {
+			return nil, unsupportedSchemeError(pth, scheme)
+		}
+	default:
+		return func(p string) ([]byte, error) {
+			lpth, err := o.localPath(p)
+			if err != nil {
+				return nil, err
 			}
-		} else {
-			// no host, let's figure out if this is a drive letter
-			upth = strings.TrimPrefix(upth, `file://`)
-			first, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
-			if strings.HasSuffix(first, ":") {
-				// drive letter in the first segment:
-				// file:///c:/folder/... ==> strip the leading slash
-				upth = strings.TrimPrefix(upth, `/`)
+
+			data, err := local(lpth)
+			if err != nil {
+				return nil, localLoadError(p, lpth, err)
 			}
-		}
 
-		return local(filepath.FromSlash(upth))
+			return checkMaxBytes(data, p, o.maxBytes)
+		}
 	}
 }
 
-func loadHTTPBytes(opts ...Option) func(path string) ([]byte, error) {
-	o := optionsWithDefaults(opts)
+type strategyKind uint8
 
-	return func(path string) ([]byte, error) {
-		client := o.client
-		timeoutCtx := context.Background()
-		var cancel func()
+const (
+	strategyLocal strategyKind = iota
+	strategyRemote
+	strategyData
+	strategyRegistered
+	strategyArchive
+	strategyUnsupported
+)
 
-		if o.httpTimeout > 0 {
-			timeoutCtx, cancel = context.WithTimeout(timeoutCtx, o.httpTimeout)
-			defer cancel()
-		}
+// strategyFor determines how to load a path, based on its scheme.
+func (o options) strategyFor(pth string) (strategyKind, string) {
+	scheme, hasScheme := uriScheme(pth)
 
-		req, err := http.NewRequestWithContext(timeoutCtx, http.MethodGet, path, nil)
-		if err != nil {
-			return nil, err
+	if archive, _, isArchive := splitArchivePath(pth); isArchive && scheme != schemeData {
+		if kind, _ := o.strategyFor(archive); kind == strategyUnsupported {
+			return strategyUnsupported, scheme
 		}
 
-		if o.basicAuthUsername != "" && o.basicAuthPassword != "" {
-			req.SetBasicAuth(o.basicAuthUsername, o.basicAuthPassword)
-		}
+		return strategyArchive, scheme
+	}
 
-		for key, val := range o.customHeaders {
-			req.Header.Set(key, val)
-		}
+	if !hasScheme {
+		return strategyLocal, ""
+	}
 
-		resp, err := client.Do(req)
-		defer func() {
-			if resp != nil {
-				if e := resp.Body.Close(); e != nil {
-					log.Println(e)
-				}
-			}
-		}()
-		if err != nil {
-			return nil, err
-		}
+	if _, isRegistered := o.schemes[scheme]; isRegistered {
+		return strategyRegistered, scheme
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("could not access document at %q [%s]: %w", path, resp.Status, ErrLoader)
+	switch scheme {
+	case schemeHTTP, schemeHTTPS:
+		return strategyRemote, scheme
+	case schemeData:
+		return strategyData, scheme
+	case schemeFile:
+		return strategyLocal, scheme
+	default:
+		return strategyUnsupported, scheme
+	}
+}
+
+// localLoadError wraps the errors from the file system, e.g. a missing file, in a [LoadError].
+func localLoadError(pth, name string, err error) error {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return err
+	}
+
+	return &LoadError{Path: pth, URL: name, Err: err}
+}
+
+func unsupportedSchemeError(pth, scheme string) error {
+	return fmt.Errorf("unsupported URI scheme %q in %q: %w", scheme, pth, ErrLoader)
+}
+
+// localPath transforms a path or a file URI into a path suitable for the local loader.
+//
+// See [LoadStrategy] for the details of the applied transforms.
+func (o options) localPath(p string) (string, error) {
+	_, isEmbedFS := o.fs.(embed.FS)
+
+	upth, err := url.PathUnescape(p)
+	if err != nil {
+		return "", err
+	}
+
+	cpth, hasPrefix := strings.CutPrefix(upth, "file://")
+	if !hasPrefix || isEmbedFS || runtime.GOOS != "windows" {
+		// crude processing: trim the file:// prefix. This leaves full URIs with a host with a (mostly) unexpected result
+		// regular file path provided: just normalize slashes
+		if isEmbedFS {
+			// on windows, we need to slash the path if FS is an embed FS.
+			return strings.TrimLeft(filepath.ToSlash(cpth), "./"), nil // remove invalid leading characters for embed FS
 		}
 
-		return io.ReadAll(resp.Body)
+		return filepath.FromSlash(cpth), nil
 	}
+
+	// windows-only pre-processing of file://... URIs, excluding embed.FS
+
+	// support for canonical file URIs on windows.
+	u, err := url.Parse(filepath.ToSlash(upth))
+	if err != nil {
+		return "", err
+	}
+
+	if u.Host != "" {
+		// assume UNC name (volume share)
+		// NOTE: UNC port not yet supported
+
+		// when the "host" segment is a drive letter:
+		// file://C:/folder/... => C:\folder
+		upth = path.Clean(strings.Join([]string{u.Host, u.Path}, `/`))
+		if !strings.HasSuffix(u.Host, ":") && u.Host[0] != '.' {
+			// tolerance: if we have a leading dot, this can't be a host
+			// file://host/share/folder\... ==> \\host\share\path\folder
+			upth = "//" + upth
+		}
+	} else {
+		// no host, let's figure out if this is a drive letter
+		upth = strings.TrimPrefix(upth, `file://`)
+		first, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+		if strings.HasSuffix(first, ":") {
+			// drive letter in the first segment:
+			// file:///c:/folder/... ==> strip the leading slash
+			upth = strings.TrimPrefix(upth, `/`)
+		}
+	}
+
+	return filepath.FromSlash(upth), nil
 }
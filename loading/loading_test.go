@@ -195,6 +195,118 @@ func TestLoadFromHTTP(t *testing.T) {
 	})
 }
 
+func TestLoadFromFileOrHTTPContext(t *testing.T) {
+	t.Run("should load with a background context", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(serveOK))
+		defer ts.Close()
+
+		d, err := LoadFromFileOrHTTPContext(context.Background(), ts.URL)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("the content"), d)
+	})
+
+	t.Run("should abort remote load when the context is cancelled", func(t *testing.T) {
+		release := make(chan struct{})
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			<-release
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer serv.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := LoadFromFileOrHTTPContext(ctx, serv.URL)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should combine the context deadline with the loader timeout", func(t *testing.T) {
+		const delay = 50 * time.Millisecond
+
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			time.Sleep(delay)
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer serv.Close()
+
+		t.Run("with a context deadline shorter than the timeout", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), delay/5)
+			defer cancel()
+
+			_, err := LoadFromFileOrHTTPContext(ctx, serv.URL, WithTimeout(time.Minute))
+			require.Error(t, err)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+
+		t.Run("with a timeout shorter than the context deadline", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			_, err := LoadFromFileOrHTTPContext(ctx, serv.URL, WithTimeout(delay/5))
+			require.Error(t, err)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+
+		t.Run("with no timeout, the context deadline still applies", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), delay/5)
+			defer cancel()
+
+			_, err := LoadFromFileOrHTTPContext(ctx, serv.URL, WithTimeout(0))
+			require.Error(t, err)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	})
+
+	t.Run("should load from a local file system with a cancellable context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		b, err := LoadFromFileOrHTTPContext(ctx, "fixtures/petstore_fixture.yaml",
+			WithFS(embeddedFixtures),
+		)
+		require.NoError(t, err)
+		assert.YAMLEqT(t, string(yamlPetStore), string(b))
+	})
+
+	t.Run("should load from the os file system with a cancellable context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		b, err := LoadFromFileOrHTTPContext(ctx, filepath.Join("fixtures", "petstore_fixture.json"))
+		require.NoError(t, err)
+		assert.JSONEqBytes(t, jsonPetStore, b)
+	})
+
+	t.Run("should not load from a local file system with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := LoadFromFileOrHTTPContext(ctx, "fixtures/petstore_fixture.yaml",
+			WithFS(embeddedFixtures),
+		)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should abort a slow local read when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		slow := slowFS{
+			MapFS: fstest.MapFS{
+				"file": &fstest.MapFile{Data: []byte("slowly read content"), Mode: fs.ModePerm},
+			},
+			onRead: cancel, // the context is cancelled while reading
+		}
+
+		_, err := LoadFromFileOrHTTPContext(ctx, "file", WithFS(slow))
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 func TestLoadStrategy(t *testing.T) {
 	const thisIsNotIt = "not it"
 	loader := func(_ string) ([]byte, error) {
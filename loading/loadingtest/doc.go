@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package loadingtest provides a record/replay HTTP transport, to test code that loads remote documents
+// with package [loading], without accessing the network.
+//
+// A recorder fetches remote documents and saves every response to a fixtures directory,
+// or to a single zip archive. A replayer serves responses only from these fixtures.
+//
+// [loading]: https://pkg.go.dev/github.com/go-openapi/swag/loading
+package loadingtest
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loadingtest
+
+type transportError string
+
+const (
+	// ErrNotRecorded is returned when replaying a request that was not recorded.
+	ErrNotRecorded transportError = "no recorded response"
+)
+
+func (e transportError) Error() string {
+	return string(e)
+}
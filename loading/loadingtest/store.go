@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loadingtest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// recording is a recorded response, as saved in the fixtures.
+type recording struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// Body holds a text response body. BinaryBody holds any other response body.
+	Body       string `json:"body,omitempty"`
+	BinaryBody []byte `json:"binaryBody,omitempty"`
+}
+
+func newRecording(req *http.Request, resp *http.Response, body []byte) *recording {
+	rec := &recording{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+
+	if utf8.Valid(body) {
+		rec.Body = string(body)
+	} else {
+		rec.BinaryBody = body
+	}
+
+	return rec
+}
+
+func (r *recording) response(req *http.Request) *http.Response {
+	body := r.BinaryBody
+	if body == nil {
+		body = []byte(r.Body)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// recordingKey identifies the recording for a request: its method and URL, without fragment.
+func recordingKey(req *http.Request) string {
+	u := *req.URL
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return req.Method + " " + u.String()
+}
+
+// fixtureName is the name of the file that holds a recording, e.g. "example.com_specs_pet.yaml-1a2b3c4d.json".
+//
+// The name is readable, and a hash of the key avoids collisions.
+func fixtureName(key string) string {
+	const hashLen = 8
+
+	sum := sha256.Sum256([]byte(key))
+	_, rawURL, _ := strings.Cut(key, " ")
+	_, rest, found := strings.Cut(rawURL, "://")
+	if !found {
+		rest = rawURL
+	}
+
+	readable := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimSuffix(rest, "/"))
+
+	return readable + "-" + hex.EncodeToString(sum[:])[:hashLen] + ".json"
+}
+
+type store interface {
+	load(key string) (*recording, error)
+	save(key string, rec *recording) error
+	flush() error
+}
+
+// dirStore keeps fixtures as files in a directory.
+type dirStore struct {
+	dir string
+}
+
+func newDirStore(dir string) (*dirStore, error) {
+	return &dirStore{dir: dir}, nil
+}
+
+func (s *dirStore) load(key string) (*recording, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, fixtureName(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRecording(data)
+}
+
+func (s *dirStore) save(key string, rec *recording) error {
+	data, err := encodeRecording(rec)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(s.dir, 0o755); err != nil { //nolint:mnd,gosec // regular permissions for fixtures
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(s.dir, fixtureName(key)), data, 0o644) //nolint:mnd,gosec // regular permissions for fixtures
+}
+
+func (s *dirStore) flush() error {
+	return nil
+}
+
+// archiveStore keeps fixtures in a single zip archive.
+//
+// Fixtures are held in memory, and written to the archive on flush.
+type archiveStore struct {
+	path       string
+	recordings map[string][]byte
+}
+
+func newArchiveStore(path string, mustExist bool) (*archiveStore, error) {
+	s := &archiveStore{
+		path:       path,
+		recordings: make(map[string][]byte),
+	}
+
+	zr, err := zip.OpenReader(path)
+	if errors.Is(err, fs.ErrNotExist) && !mustExist {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+
+	for _, file := range zr.File {
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		s.recordings[file.Name] = data
+	}
+
+	return s, nil
+}
+
+func (s *archiveStore) load(key string) (*recording, error) {
+	data, found := s.recordings[fixtureName(key)]
+	if !found {
+		return nil, nil
+	}
+
+	return decodeRecording(data)
+}
+
+func (s *archiveStore) save(key string, rec *recording) error {
+	data, err := encodeRecording(rec)
+	if err != nil {
+		return err
+	}
+
+	s.recordings[fixtureName(key)] = data
+
+	return nil
+}
+
+func (s *archiveStore) flush() error {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	names := make([]string, 0, len(s.recordings))
+	for name := range s.recordings {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err = w.Write(s.recordings[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil { //nolint:mnd,gosec // regular permissions for fixtures
+		return err
+	}
+
+	return os.WriteFile(s.path, buf.Bytes(), 0o644) //nolint:mnd,gosec // regular permissions for fixtures
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rdr, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rdr.Close()
+	}()
+
+	return io.ReadAll(rdr)
+}
+
+func encodeRecording(rec *recording) ([]byte, error) {
+	return json.MarshalIndent(rec, "", "  ")
+}
+
+func decodeRecording(data []byte) (*recording, error) {
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	return &rec, nil
+}
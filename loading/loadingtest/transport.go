@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loadingtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/swag/loading"
+)
+
+// Mode tells whether a [Transport] records or replays responses.
+type Mode uint8
+
+const (
+	// Replay serves responses from the fixtures, and fails on any request that was not recorded.
+	Replay Mode = iota
+
+	// Record sends requests to the network, and saves every response to the fixtures.
+	//
+	// Conditional requests, e.g. by a loader with a cache, are sent without their validators
+	// ("If-None-Match" and "If-Modified-Since"), so that a full response is always recorded.
+	Record
+)
+
+// Transport is an [http.RoundTripper] that records or replays responses.
+//
+// Fixtures are stored in a directory, or in a single zip archive when the path ends with ".zip".
+//
+// A [Transport] is safe for concurrent use.
+type Transport struct {
+	mode  Mode
+	next  http.RoundTripper
+	store store
+
+	mx sync.Mutex
+}
+
+// New builds a [Transport] that records or replays responses, with fixtures stored at path.
+//
+// When recording, requests are sent with the next [http.RoundTripper], or [http.DefaultTransport] if nil.
+//
+// Recorded fixtures are saved to a zip archive only when the [Transport] is closed.
+func New(mode Mode, path string, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	var (
+		s   store
+		err error
+	)
+
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		s, err = newArchiveStore(path, mode == Replay)
+	} else {
+		s, err = newDirStore(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transport{
+		mode:  mode,
+		next:  next,
+		store: s,
+	}, nil
+}
+
+// NewRecorder builds a [Transport] that records responses to path.
+//
+// See [New].
+func NewRecorder(path string, next http.RoundTripper) (*Transport, error) {
+	return New(Record, path, next)
+}
+
+// NewReplayer builds a [Transport] that replays responses from path.
+//
+// See [New].
+func NewReplayer(path string) (*Transport, error) {
+	return New(Replay, path, nil)
+}
+
+// Client returns an [http.Client] that uses this [Transport].
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Option returns a [loading.Option] to load remote documents with this [Transport].
+func (t *Transport) Option() loading.Option {
+	return loading.WithHTTPClient(t.Client())
+}
+
+// Close saves the recorded fixtures, when they are stored in a zip archive.
+func (t *Transport) Close() error {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.mode != Record {
+		return nil
+	}
+
+	return t.store.flush()
+}
+
+// RoundTrip records or replays a response.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == Replay {
+		return t.replay(req)
+	}
+
+	return t.record(req)
+}
+
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	key := recordingKey(req)
+
+	t.mx.Lock()
+	rec, err := t.store.load(key)
+	t.mx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if rec == nil {
+		return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, key)
+	}
+
+	return rec.response(req), nil
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		// a 304 response would replace the recorded document
+		req = req.Clone(req.Context())
+		req.Header.Del("If-None-Match")
+		req.Header.Del("If-Modified-Since")
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := newRecording(req, resp, body)
+
+	t.mx.Lock()
+	err = t.store.save(recordingKey(req), rec)
+	t.mx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	replayed := *resp
+	replayed.Body = io.NopCloser(bytes.NewReader(body))
+
+	return &replayed, nil
+}
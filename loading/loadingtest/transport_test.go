@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loadingtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const petJSON = `{"type":"object","properties":{"name":{"type":"string"}}}`
+
+func TestTransport(t *testing.T) {
+	for _, fixtures := range []string{"fixtures", "fixtures.zip"} {
+		t.Run("with "+fixtures, func(t *testing.T) {
+			pth := filepath.Join(t.TempDir(), fixtures)
+
+			var requests atomic.Int32
+			serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				switch r.URL.Path {
+				case "/moved.json":
+					http.Redirect(rw, r, "/pet.json", http.StatusFound)
+				case "/logo.bin":
+					_, _ = rw.Write([]byte{0xff, 0xfe, 0x00, 0x01})
+				default:
+					rw.Header().Set("Content-Type", "application/json")
+					_, _ = rw.Write([]byte(petJSON))
+				}
+			}))
+			baseURL := serv.URL
+
+			t.Run("should record responses", func(t *testing.T) {
+				recorder, err := NewRecorder(pth, nil)
+				require.NoError(t, err)
+
+				doc, err := loading.JSONDoc(baseURL+"/moved.json", recorder.Option())
+				require.NoError(t, err)
+				assert.JSONEqT(t, petJSON, string(doc))
+
+				b, err := loading.LoadFromFileOrHTTP(baseURL+"/logo.bin", recorder.Option())
+				require.NoError(t, err)
+				assert.Equal(t, []byte{0xff, 0xfe, 0x00, 0x01}, b)
+
+				require.NoError(t, recorder.Close())
+				assert.EqualT(t, int32(3), requests.Load())
+			})
+
+			serv.Close()
+
+			t.Run("should replay responses", func(t *testing.T) {
+				replayer, err := NewReplayer(pth)
+				require.NoError(t, err)
+				t.Cleanup(func() {
+					require.NoError(t, replayer.Close())
+				})
+
+				doc, err := loading.JSONDoc(baseURL+"/moved.json#/properties", replayer.Option())
+				require.NoError(t, err)
+				assert.JSONEqT(t, petJSON, string(doc))
+
+				rdr, meta, err := loading.Open(baseURL+"/pet.json", loading.WithHTTPClient(replayer.Client()))
+				require.NoError(t, err)
+				require.NoError(t, rdr.Close())
+				assert.EqualT(t, "application/json", meta.ContentType)
+
+				b, err := loading.LoadFromFileOrHTTP(baseURL+"/logo.bin", replayer.Option())
+				require.NoError(t, err)
+				assert.Equal(t, []byte{0xff, 0xfe, 0x00, 0x01}, b)
+			})
+
+			t.Run("should fail on a request that was not recorded", func(t *testing.T) {
+				replayer, err := NewReplayer(pth)
+				require.NoError(t, err)
+
+				_, err = loading.JSONDoc(baseURL+"/unknown.json", replayer.Option())
+				require.Error(t, err)
+				require.ErrorIs(t, err, ErrNotRecorded)
+				require.ErrorIs(t, err, loading.ErrLoader)
+			})
+		})
+	}
+
+	t.Run("should fail to replay from a missing archive", func(t *testing.T) {
+		_, err := NewReplayer(filepath.Join(t.TempDir(), "missing.zip"))
+		require.Error(t, err)
+	})
+
+	t.Run("should replay nothing from a missing directory", func(t *testing.T) {
+		replayer, err := NewReplayer(filepath.Join(t.TempDir(), "missing"))
+		require.NoError(t, err)
+
+		_, err = loading.LoadFromFileOrHTTP("https://example.com/pet.json", replayer.Option())
+		require.ErrorIs(t, err, ErrNotRecorded)
+	})
+}
+
+func TestTransportWithCache(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "fixtures")
+	serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		_, _ = rw.Write([]byte(petJSON))
+	}))
+	baseURL := serv.URL
+
+	t.Run("should record full responses to conditional requests", func(t *testing.T) {
+		recorder, err := NewRecorder(pth, nil)
+		require.NoError(t, err)
+		cache := loading.NewMemoryCache()
+
+		for range 2 {
+			doc, err := loading.JSONDoc(baseURL+"/pet.json", recorder.Option(), loading.WithCache(cache))
+			require.NoError(t, err)
+			assert.JSONEqT(t, petJSON, string(doc))
+		}
+
+		require.NoError(t, recorder.Close())
+	})
+
+	serv.Close()
+
+	t.Run("should replay without a cache", func(t *testing.T) {
+		replayer, err := NewReplayer(pth)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, replayer.Close())
+		})
+
+		doc, err := loading.JSONDoc(baseURL+"/pet.json", replayer.Option())
+		require.NoError(t, err)
+		assert.JSONEqT(t, petJSON, string(doc))
+	})
+}
+
+func TestFixtureName(t *testing.T) {
+	name := fixtureName("GET https://example.com/specs/pet.yaml?v=1")
+	assert.Regexp(t, `^example\.com_specs_pet\.yaml_v_1-[0-9a-f]{8}\.json$`, name)
+	assert.NotEqualT(t, name, fixtureName("GET https://example.com/specs/pet.yaml?v=2"))
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+)
+
+// Lockfile records the integrity of the documents loaded during a run, e.g. to pin third-party specifications.
+//
+// When set with [WithLockfile], the digest of every loaded document is recorded. A document that is already
+// recorded must match its recorded integrity, or an [IntegrityError] is raised.
+//
+// A [Lockfile] is saved as a JSON object, which maps the paths or URLs of the documents to their integrity,
+// e.g.:
+//
+//	{
+//	  "https://example.com/pet.yaml": "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+//	}
+//
+// A [Lockfile] may be shared by concurrent loads.
+type Lockfile struct {
+	mx      sync.Mutex
+	digests map[string]string
+}
+
+// NewLockfile builds an empty [Lockfile].
+func NewLockfile() *Lockfile {
+	return &Lockfile{
+		digests: make(map[string]string),
+	}
+}
+
+// ReadLockfile reads a [Lockfile] previously saved with [Lockfile.WriteTo].
+func ReadLockfile(r io.Reader) (*Lockfile, error) {
+	lock := NewLockfile()
+	if err := json.NewDecoder(r).Decode(&lock.digests); err != nil {
+		return nil, fmt.Errorf("invalid lockfile: %w: %w", err, ErrLoader)
+	}
+
+	return lock, nil
+}
+
+// Integrity returns the recorded integrity of a document, if any.
+func (l *Lockfile) Integrity(path string) (string, bool) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	integrity, found := l.digests[path]
+
+	return integrity, found
+}
+
+// Digests returns all recorded documents, with their integrity.
+func (l *Lockfile) Digests() map[string]string {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return maps.Clone(l.digests)
+}
+
+// WriteTo saves the [Lockfile] as JSON, sorted by path.
+func (l *Lockfile) WriteTo(w io.Writer) (int64, error) {
+	l.mx.Lock()
+	data, err := json.MarshalIndent(l.digests, "", "  ")
+	l.mx.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(data, '\n'))
+
+	return int64(n), err
+}
+
+// verifyOrRecord checks a document against its recorded integrity, or records it.
+func (l *Lockfile) verifyOrRecord(pth string, digestOf func(int) string) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if expected, found := l.digests[pth]; found {
+		return checkIntegrity(pth, digestOf, expected)
+	}
+
+	l.digests[pth] = digestOf(0) // sha256
+
+	return nil
+}
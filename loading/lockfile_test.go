@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestLockfile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pet.json":  &fstest.MapFile{Data: jsonPetStore},
+		"pet.yaml":  &fstest.MapFile{Data: yamlPetStore},
+		"other.txt": &fstest.MapFile{Data: []byte("other")},
+	}
+
+	t.Run("should record the documents loaded", func(t *testing.T) {
+		lock := NewLockfile()
+
+		_, err := JSONDoc("pet.json", WithFS(fsys), WithLockfile(lock))
+		require.NoError(t, err)
+
+		rdr, _, err := Open("pet.yaml", WithFS(fsys), WithLockfile(lock))
+		require.NoError(t, err)
+		_, err = io.ReadAll(rdr)
+		require.NoError(t, err)
+		require.NoError(t, rdr.Close())
+
+		assert.Equal(t, map[string]string{
+			"pet.json": Integrity(jsonPetStore),
+			"pet.yaml": Integrity(yamlPetStore),
+		}, lock.Digests())
+
+		integrity, found := lock.Integrity("pet.json")
+		require.TrueT(t, found)
+		assert.EqualT(t, Integrity(jsonPetStore), integrity)
+	})
+
+	t.Run("should save and read a lockfile", func(t *testing.T) {
+		lock := NewLockfile()
+		_, err := LoadFromFileOrHTTP("pet.json", WithFS(fsys), WithLockfile(lock))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		n, err := lock.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.EqualT(t, int64(buf.Len()), n)
+		assert.JSONEqT(t, `{"pet.json":"`+Integrity(jsonPetStore)+`"}`, buf.String())
+
+		read, err := ReadLockfile(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, lock.Digests(), read.Digests())
+	})
+
+	t.Run("should verify recorded documents", func(t *testing.T) {
+		lock, err := ReadLockfile(strings.NewReader(`{"pet.json": "` + Integrity([]byte("pinned")) + `"}`))
+		require.NoError(t, err)
+
+		_, err = LoadFromFileOrHTTP("pet.json", WithFS(fsys), WithLockfile(lock))
+		require.Error(t, err)
+
+		var integrityErr *IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+
+		_, err = LoadFromFileOrHTTP("other.txt", WithFS(fsys), WithLockfile(lock))
+		require.NoError(t, err)
+		assert.Len(t, lock.Digests(), 2)
+	})
+
+	t.Run("should fail on an invalid lockfile", func(t *testing.T) {
+		_, err := ReadLockfile(strings.NewReader(`[]`))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+}
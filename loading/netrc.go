@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// netrcMachine is an entry of a .netrc file.
+//
+// The "default" entry has an empty name.
+type netrcMachine struct {
+	name     string
+	login    string
+	password string
+}
+
+// defaultNetrcPath is the location of the .netrc file: $NETRC, or ~/.netrc (~/_netrc on windows).
+func defaultNetrcPath() string {
+	if pth := os.Getenv("NETRC"); pth != "" {
+		return pth
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+
+	return filepath.Join(home, ".netrc")
+}
+
+// readNetrc reads the entries of a .netrc file.
+//
+// When the file is not explicitly set, a missing file is not an error.
+func readNetrc(pth string) ([]netrcMachine, error) {
+	explicit := pth != ""
+	if !explicit {
+		pth = defaultNetrcPath()
+		if pth == "" {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(pth)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read netrc file: %w: %w", err, ErrLoader)
+	}
+
+	machines, err := parseNetrc(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid netrc file %q: %w: %w", pth, err, ErrLoader)
+	}
+
+	return machines, nil
+}
+
+// parseNetrc parses the content of a .netrc file.
+//
+// Macro definitions ("macdef") are skipped, and so are lines starting with "#".
+func parseNetrc(data []byte) ([]netrcMachine, error) {
+	var (
+		machines []netrcMachine
+		current  *netrcMachine
+		inMacro  bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if inMacro {
+			// a macro definition ends with an empty line
+			inMacro = strings.TrimSpace(line) != ""
+
+			continue
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		tokens := strings.Fields(line)
+		for i := 0; i < len(tokens); i++ {
+			keyword := tokens[i]
+
+			switch keyword {
+			case "default":
+				machines = append(machines, netrcMachine{})
+				current = &machines[len(machines)-1]
+
+				continue
+			case "macdef":
+				inMacro = true
+				i = len(tokens)
+
+				continue
+			}
+
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("missing value for %q", keyword)
+			}
+			i++
+			value := tokens[i]
+
+			if keyword != "machine" && current == nil {
+				return nil, fmt.Errorf("%q outside of a machine entry", keyword)
+			}
+
+			switch keyword {
+			case "machine":
+				machines = append(machines, netrcMachine{name: value})
+				current = &machines[len(machines)-1]
+			case "login":
+				current.login = value
+			case "password":
+				current.password = value
+			case "account":
+				// not used
+			default:
+				return nil, fmt.Errorf("unexpected token %q", keyword)
+			}
+		}
+	}
+
+	return machines, scanner.Err()
+}
+
+// findNetrcMachine finds the entry for a host name, or the "default" entry when the name is empty.
+func findNetrcMachine(machines []netrcMachine, name string) (netrcMachine, bool) {
+	for _, machine := range machines {
+		if strings.EqualFold(machine.name, name) && machine.login != "" {
+			return machine, true
+		}
+	}
+
+	return netrcMachine{}, false
+}
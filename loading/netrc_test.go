@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestParseNetrc(t *testing.T) {
+	t.Run("should parse machine entries", func(t *testing.T) {
+		machines, err := parseNetrc([]byte(`# credentials
+machine api.example.com login alice password s3cret
+machine other.example.com
+  login bob
+  account ignored
+  password pa55
+
+macdef init
+cd /pub
+bin
+
+default login anonymous password guest
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []netrcMachine{
+			{name: "api.example.com", login: "alice", password: "s3cret"},
+			{name: "other.example.com", login: "bob", password: "pa55"},
+			{login: "anonymous", password: "guest"},
+		}, machines)
+
+		machine, ok := findNetrcMachine(machines, "API.example.com")
+		require.TrueT(t, ok)
+		assert.EqualT(t, "alice", machine.login)
+
+		machine, ok = findNetrcMachine(machines, "")
+		require.TrueT(t, ok)
+		assert.EqualT(t, "anonymous", machine.login)
+
+		_, ok = findNetrcMachine(machines, "unknown.example.com")
+		assert.FalseT(t, ok)
+	})
+
+	t.Run("should fail on a missing value", func(t *testing.T) {
+		_, err := parseNetrc([]byte("machine api.example.com login"))
+		require.Error(t, err)
+	})
+
+	t.Run("should fail on a login outside of a machine entry", func(t *testing.T) {
+		_, err := parseNetrc([]byte("login alice"))
+		require.Error(t, err)
+	})
+
+	t.Run("should fail on an unexpected token", func(t *testing.T) {
+		_, err := parseNetrc([]byte("machine api.example.com user alice"))
+		require.Error(t, err)
+	})
+}
+
+func TestReadNetrc(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, "netrc")
+	require.NoError(t, os.WriteFile(pth, []byte("machine api.example.com login alice password s3cret\n"), 0o600))
+
+	t.Run("should read an explicit file", func(t *testing.T) {
+		machines, err := readNetrc(pth)
+		require.NoError(t, err)
+		require.Len(t, machines, 1)
+	})
+
+	t.Run("should read the file set by $NETRC", func(t *testing.T) {
+		t.Setenv("NETRC", pth)
+
+		machines, err := readNetrc("")
+		require.NoError(t, err)
+		require.Len(t, machines, 1)
+	})
+
+	t.Run("should ignore a missing default file", func(t *testing.T) {
+		t.Setenv("NETRC", filepath.Join(dir, "missing"))
+
+		machines, err := readNetrc("")
+		require.NoError(t, err)
+		assert.Empty(t, machines)
+	})
+
+	t.Run("should fail on a missing explicit file", func(t *testing.T) {
+		_, err := readNetrc(filepath.Join(dir, "missing"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should fail on an invalid file", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid")
+		require.NoError(t, os.WriteFile(invalid, []byte("password"), 0o600))
+
+		_, err := readNetrc(invalid)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+}
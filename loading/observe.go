@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hooks observe the activity of the loader, e.g. to produce logs, metrics or traces.
+//
+// All hooks are optional. They are called synchronously, possibly from several goroutines at once
+// (e.g. with a [BatchLoader]): they should return quickly and be safe for concurrent use.
+type Hooks struct {
+	// OnStart is called when the load of a document starts.
+	OnStart func(context.Context, Event)
+
+	// OnRedirect is called when a remote request is redirected. The event holds the new URL,
+	// and the status code of the redirect response.
+	OnRedirect func(context.Context, Event)
+
+	// OnResponse is called when the body of a remote response is closed, i.e. once per HTTP exchange,
+	// including retried attempts. The event holds the status code, the number of bytes read from the body,
+	// and the time elapsed since the request was sent.
+	OnResponse func(context.Context, Event)
+
+	// OnCacheHit is called when a remote document is served from the [Cache], either because it is fresh or
+	// because the server responded with the status 304 "Not Modified".
+	OnCacheHit func(context.Context, Event)
+
+	// OnError is called when the load of a document fails, or when closing a response body fails.
+	OnError func(context.Context, Event)
+}
+
+// Event describes a step in the load of a document, as reported to [Hooks].
+type Event struct {
+	// Path is the path or URL of the document, as requested.
+	Path string
+
+	// URL is the URL involved in a remote exchange, after rewrites and redirects.
+	URL string
+
+	// StatusCode is the HTTP status code of a response, or 0.
+	StatusCode int
+
+	// Bytes is a number of bytes: read from a response body, or served from the cache.
+	Bytes int64
+
+	// Duration is the time elapsed since the start of the load, or since the request was sent for a response.
+	Duration time.Duration
+
+	// Err is the error that occurred, for OnError.
+	Err error
+}
+
+// SlogHooks builds [Hooks] that log all events with a [slog.Logger].
+//
+// Errors are logged at the level [slog.LevelError], and other events at the level [slog.LevelDebug].
+func SlogHooks(logger *slog.Logger) Hooks {
+	log := func(level slog.Level, msg string) func(context.Context, Event) {
+		return func(ctx context.Context, e Event) {
+			if !logger.Enabled(ctx, level) {
+				return
+			}
+
+			var attrs []slog.Attr
+			if e.Path != "" {
+				attrs = append(attrs, slog.String("path", e.Path))
+			}
+			if e.URL != "" {
+				attrs = append(attrs, slog.String("url", e.URL))
+			}
+			if e.StatusCode != 0 {
+				attrs = append(attrs, slog.Int("status", e.StatusCode))
+			}
+			if e.Bytes != 0 {
+				attrs = append(attrs, slog.Int64("bytes", e.Bytes))
+			}
+			if e.Duration != 0 {
+				attrs = append(attrs, slog.Duration("duration", e.Duration))
+			}
+			if e.Err != nil {
+				attrs = append(attrs, slog.Any("error", e.Err))
+			}
+
+			logger.LogAttrs(ctx, level, msg, attrs...)
+		}
+	}
+
+	return Hooks{
+		OnStart:    log(slog.LevelDebug, "loading document"),
+		OnRedirect: log(slog.LevelDebug, "redirected"),
+		OnResponse: log(slog.LevelDebug, "received response"),
+		OnCacheHit: log(slog.LevelDebug, "served from cache"),
+		OnError:    log(slog.LevelError, "could not load document"),
+	}
+}
+
+// notify calls the hook selected for all the [Hooks] installed.
+func (o options) notify(ctx context.Context, hook func(Hooks) func(context.Context, Event), e Event) {
+	for _, hooks := range o.hooks {
+		if fn := hook(hooks); fn != nil {
+			fn(ctx, e)
+		}
+	}
+}
+
+func onStart(h Hooks) func(context.Context, Event)    { return h.OnStart }
+func onRedirect(h Hooks) func(context.Context, Event) { return h.OnRedirect }
+func onResponse(h Hooks) func(context.Context, Event) { return h.OnResponse }
+func onCacheHit(h Hooks) func(context.Context, Event) { return h.OnCacheHit }
+func onError(h Hooks) func(context.Context, Event)    { return h.OnError }
+
+// observeLoad notifies the [Hooks] of the start of a load, and of its failure.
+func observeLoad[T any](ctx context.Context, o options, pth string, load func() (T, error)) (T, error) {
+	if len(o.hooks) == 0 {
+		return load()
+	}
+
+	start := time.Now()
+	o.notify(ctx, onStart, Event{Path: pth})
+
+	data, err := load()
+	if err != nil {
+		o.notify(ctx, onError, Event{Path: pth, Duration: time.Since(start), Err: err})
+	}
+
+	return data, err
+}
+
+// observeResponse wraps the body of a response, so that the [Hooks] are notified when it is closed.
+func (o options) observeResponse(ctx context.Context, path string, resp *http.Response, sent time.Time) {
+	if len(o.hooks) == 0 {
+		return
+	}
+
+	body := &observedBody{ReadCloser: resp.Body}
+	body.onClose = func() {
+		o.notify(ctx, onResponse, Event{
+			Path:       path,
+			URL:        resp.Request.URL.String(),
+			StatusCode: resp.StatusCode,
+			Bytes:      body.bytes.Load(),
+			Duration:   time.Since(sent),
+		})
+	}
+	resp.Body = body
+}
+
+// closeResponse closes the body of a response. A failure is reported to the OnError hooks.
+func (o options) closeResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		o.notify(resp.Request.Context(), onError, Event{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode, Err: err})
+	}
+}
+
+// observedBody counts the bytes read from a response body.
+type observedBody struct {
+	io.ReadCloser
+
+	bytes   atomic.Int64
+	once    sync.Once
+	onClose func()
+}
+
+func (b *observedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+
+	return n, err
+}
+
+func (b *observedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.onClose)
+
+	return err
+}
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestHooks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pet.json", serveJSONPetStore)
+	mux.HandleFunc("/missing.json", serveKO)
+	mux.Handle("/moved.json", http.RedirectHandler("/pet.json", http.StatusMovedPermanently))
+	mux.HandleFunc("/cached.json", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=3600")
+		serveJSONPetStore(rw, nil)
+	})
+	serv := httptest.NewServer(mux)
+	t.Cleanup(serv.Close)
+
+	t.Run("should report a successful remote load", func(t *testing.T) {
+		rec := &eventRecorder{}
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/moved.json", WithHooks(rec.hooks()))
+		require.NoError(t, err)
+
+		events := rec.all()
+		require.Len(t, events, 3)
+
+		assert.EqualT(t, "start", events[0].hook)
+		assert.EqualT(t, serv.URL+"/moved.json", events[0].Path)
+
+		assert.EqualT(t, "redirect", events[1].hook)
+		assert.EqualT(t, serv.URL+"/pet.json", events[1].URL)
+		assert.EqualT(t, http.StatusMovedPermanently, events[1].StatusCode)
+
+		assert.EqualT(t, "response", events[2].hook)
+		assert.EqualT(t, serv.URL+"/pet.json", events[2].URL)
+		assert.EqualT(t, http.StatusOK, events[2].StatusCode)
+		assert.EqualT(t, int64(len(jsonPetStore)), events[2].Bytes)
+		assert.Positive(t, events[2].Duration)
+	})
+
+	t.Run("should report a failed remote load", func(t *testing.T) {
+		rec := &eventRecorder{}
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/missing.json", WithHooks(rec.hooks()))
+		require.Error(t, err)
+
+		events := rec.all()
+		require.Len(t, events, 3)
+		assert.EqualT(t, "start", events[0].hook)
+		assert.EqualT(t, "response", events[1].hook)
+		assert.EqualT(t, http.StatusNotFound, events[1].StatusCode)
+		assert.EqualT(t, "error", events[2].hook)
+		assert.EqualT(t, serv.URL+"/missing.json", events[2].Path)
+		require.ErrorIs(t, events[2].Err, ErrLoader)
+	})
+
+	t.Run("should report a failed local load", func(t *testing.T) {
+		rec := &eventRecorder{}
+
+		_, err := Doc("fixtures/nowhere.yaml", WithHooks(rec.hooks()))
+		require.Error(t, err)
+
+		events := rec.all()
+		require.Len(t, events, 2)
+		assert.EqualT(t, "start", events[0].hook)
+		assert.EqualT(t, "error", events[1].hook)
+		assert.EqualT(t, "fixtures/nowhere.yaml", events[1].Path)
+	})
+
+	t.Run("should report cache hits", func(t *testing.T) {
+		rec := &eventRecorder{}
+		cache := NewMemoryCache()
+
+		for range 2 {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/cached.json", WithCache(cache), WithHooks(rec.hooks()))
+			require.NoError(t, err)
+		}
+
+		hits := rec.filter("cache")
+		require.Len(t, hits, 1)
+		assert.EqualT(t, serv.URL+"/cached.json", hits[0].URL)
+		assert.EqualT(t, int64(len(jsonPetStore)), hits[0].Bytes)
+		assert.Len(t, rec.filter("response"), 1)
+	})
+
+	t.Run("should report the bytes read from a stream when it is closed", func(t *testing.T) {
+		rec := &eventRecorder{}
+
+		rdr, _, err := Open(serv.URL+"/pet.json", WithHooks(rec.hooks()))
+		require.NoError(t, err)
+		assert.Empty(t, rec.filter("response"))
+
+		_, err = io.CopyN(io.Discard, rdr, 10)
+		require.NoError(t, err)
+		require.NoError(t, rdr.Close())
+
+		responses := rec.filter("response")
+		require.Len(t, responses, 1)
+		assert.GreaterOrEqualT(t, responses[0].Bytes, int64(10))
+	})
+
+	t.Run("should call all hooks installed", func(t *testing.T) {
+		first, second := &eventRecorder{}, &eventRecorder{}
+		loader := NewBatchLoader(WithHooks(first.hooks()), WithHooks(second.hooks()))
+
+		_, err := loader.Load(context.Background(), serv.URL+"/pet.json")
+		require.NoError(t, err)
+
+		assert.Len(t, first.all(), 2)
+		assert.Len(t, second.all(), 2)
+	})
+
+	t.Run("should report a failure to close a response body", func(t *testing.T) {
+		rec := &eventRecorder{}
+		client := &http.Client{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       failingCloser{Reader: strings.NewReader("the content")},
+					Request:    r,
+				}, nil
+			}),
+		}
+
+		_, err := LoadFromFileOrHTTP("http://example.com/doc", WithHTTPClient(client), WithHooks(rec.hooks()))
+		require.NoError(t, err)
+
+		errs := rec.filter("error")
+		require.Len(t, errs, 1)
+		assert.EqualT(t, "http://example.com/doc", errs[0].URL)
+		require.ErrorIs(t, errs[0].Err, errCloseFailed)
+	})
+}
+
+func TestWithLogger(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(serveKO))
+	t.Cleanup(serv.Close)
+
+	t.Run("should log errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/pet.json", WithLogger(logger))
+		require.Error(t, err)
+
+		out := buf.String()
+		assert.StringContainsT(t, out, "level=ERROR")
+		assert.StringContainsT(t, out, `msg="could not load document"`)
+		assert.StringContainsT(t, out, "path="+serv.URL+"/pet.json")
+		assert.NotContains(t, out, "level=DEBUG")
+	})
+
+	t.Run("should log all events at the debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		_, err := LoadFromFileOrHTTP(serv.URL+"/pet.json", WithLogger(logger))
+		require.Error(t, err)
+
+		out := buf.String()
+		assert.StringContainsT(t, out, `msg="loading document"`)
+		assert.StringContainsT(t, out, `msg="received response"`)
+		assert.StringContainsT(t, out, "status=404")
+	})
+}
+
+var errCloseFailed = errors.New("close failed")
+
+type failingCloser struct {
+	io.Reader
+}
+
+func (failingCloser) Close() error {
+	return errCloseFailed
+}
+
+type recordedEvent struct {
+	Event
+
+	hook string
+}
+
+// eventRecorder collects the events reported to [Hooks].
+type eventRecorder struct {
+	mx     sync.Mutex
+	events []recordedEvent
+}
+
+func (r *eventRecorder) hooks() Hooks {
+	record := func(hook string) func(context.Context, Event) {
+		return func(_ context.Context, e Event) {
+			r.mx.Lock()
+			defer r.mx.Unlock()
+
+			r.events = append(r.events, recordedEvent{Event: e, hook: hook})
+		}
+	}
+
+	return Hooks{
+		OnStart:    record("start"),
+		OnRedirect: record("redirect"),
+		OnResponse: record("response"),
+		OnCacheHit: record("cache"),
+		OnError:    record("error"),
+	}
+}
+
+func (r *eventRecorder) all() []recordedEvent {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return append([]recordedEvent(nil), r.events...)
+}
+
+func (r *eventRecorder) filter(hook string) []recordedEvent {
+	var filtered []recordedEvent
+
+	for _, e := range r.all() {
+		if e.hook == hook {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// Metadata describes a document opened with [Open].
+type Metadata struct {
+	// URL is the resolved location of the document.
+	//
+	// For a remote document, this is the final URL, after redirects.
+	// For a local document, this is the path of the file, after the transforms described by [LoadStrategy].
+	URL string
+
+	// ContentType is the media type of the document, if known.
+	//
+	// For a remote document, this is the "Content-Type" returned by the server.
+	// For a local document, this is inferred from the file extension.
+	ContentType string
+
+	// Size is the size of the document in bytes, or -1 if unknown (e.g. for a decompressed document).
+	Size int64
+
+	// ModTime is the last modification time of the document, or the zero time if unknown.
+	ModTime time.Time
+}
+
+// Open opens a document from either a file or a remote url, and streams its content.
+//
+// Unlike [LoadFromFileOrHTTP], the document is not buffered in memory: this is intended for large documents.
+// The caller is responsible for closing the returned [io.ReadCloser].
+//
+// Paths and URIs are resolved in the same way as [LoadStrategy] does, and all [Option] s apply.
+//
+// A few limitations apply:
+//   - retries configured with [WithRetry] only cover the establishment of the response, not reading the stream
+//   - when a [Cache] is configured with [WithCache], remote documents are buffered
+//   - documents loaded from a data URI or a loader registered with [WithSchemeLoader] are buffered
+//   - the integrity of the document (see [WithIntegrity]) is verified only when the end of the stream is reached
+func Open(path string, opts ...Option) (io.ReadCloser, Metadata, error) {
+	return OpenContext(context.Background(), path, opts...)
+}
+
+// OpenContext opens a document from either a file or a remote url, and streams its content, with a context.
+//
+// The context, as well as the timeout set by [WithTimeout], apply while the document is read.
+//
+// See [Open].
+func OpenContext(ctx context.Context, path string, opts ...Option) (io.ReadCloser, Metadata, error) {
+	o := optionsWithDefaults(opts)
+
+	var meta Metadata
+	rdr, err := observeLoad(ctx, o, path, func() (rdr io.ReadCloser, err error) {
+		rdr, meta, err = openVerified(ctx, path, o)
+
+		return rdr, err
+	})
+
+	return rdr, meta, err
+}
+
+// openVerified opens a document, and verifies its integrity at the end of the stream.
+func openVerified(ctx context.Context, path string, o options) (io.ReadCloser, Metadata, error) {
+	path, expected := o.splitIntegrity(path)
+
+	rdr, meta, err := openDocument(ctx, path, o)
+	if err != nil || (expected == "" && o.lockfile == nil) {
+		return rdr, meta, err
+	}
+
+	return readCloser{
+		Reader: newIntegrityReader(rdr, func(digestOf func(int) string) error {
+			return o.verifyDigests(path, digestOf, expected)
+		}),
+		close: rdr.Close,
+	}, meta, nil
+}
+
+func openDocument(ctx context.Context, path string, o options) (io.ReadCloser, Metadata, error) {
+	path, o, err := o.rewriteURL(path)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	kind, scheme := o.strategyFor(path)
+
+	switch kind {
+	case strategyRemote:
+		return openHTTP(ctx, path, o)
+	case strategyArchive:
+		return openArchiveEntry(ctx, path, o)
+	case strategyUnsupported:
+		return nil, Metadata{}, unsupportedSchemeError(path, scheme)
+	case strategyData, strategyRegistered:
+		data, err := loadStrategy(ctx, path, nil, nil, o)(path)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		meta := Metadata{
+			URL:  path,
+			Size: int64(len(data)),
+		}
+		if kind == strategyData {
+			meta.ContentType = dataURIMediaType(path)
+		}
+
+		return io.NopCloser(bytes.NewReader(data)), meta, nil
+	default:
+		return openLocal(ctx, path, o)
+	}
+}
+
+func openLocal(ctx context.Context, pth string, o options) (io.ReadCloser, Metadata, error) {
+	name, err := o.localPath(pth)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	file, err := o.openFile(name)
+	if err != nil {
+		return nil, Metadata{}, localLoadError(pth, name, err)
+	}
+
+	meta := Metadata{
+		URL:         name,
+		ContentType: contentTypeFromPath(name),
+		Size:        -1,
+	}
+
+	if info, statErr := file.Stat(); statErr == nil {
+		meta.Size = info.Size()
+		meta.ModTime = info.ModTime()
+
+		if o.maxBytes > 0 && meta.Size > o.maxBytes {
+			_ = file.Close()
+
+			return nil, Metadata{}, &MaxBytesError{Path: name, Limit: o.maxBytes}
+		}
+	}
+
+	rdr, closeDecompressor, isDecompressed, err := o.decompressReader(contextReader{ctx: ctx, Reader: file}, name, "")
+	if err != nil {
+		_ = file.Close()
+
+		return nil, Metadata{}, err
+	}
+
+	if isDecompressed {
+		meta.Size = -1
+	}
+
+	return readCloser{
+		Reader: newMaxBytesReader(rdr, name, o.maxBytes),
+		close: func() error {
+			return errors.Join(closeDecompressor(), file.Close())
+		},
+	}, meta, nil
+}
+
+func openArchiveEntry(ctx context.Context, pth string, o options) (io.ReadCloser, Metadata, error) {
+	fsys, entry, err := o.archiveEntry(ctx, pth, func(archive string) ([]byte, error) {
+		return strategyLoader{options: o}.load(ctx, archive)
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	file, err := fsys.Open(entry)
+	if err != nil {
+		return nil, Metadata{}, fmt.Errorf("could not open %q in archive: %w: %w", entry, err, ErrLoader)
+	}
+
+	meta := Metadata{
+		URL:         pth,
+		ContentType: contentTypeFromPath(entry),
+		Size:        -1,
+	}
+
+	if info, statErr := file.Stat(); statErr == nil {
+		meta.Size = info.Size()
+		meta.ModTime = info.ModTime()
+	}
+
+	rdr, closeDecompressor, isDecompressed, err := o.decompressReader(contextReader{ctx: ctx, Reader: file}, entry, "")
+	if err != nil {
+		_ = file.Close()
+
+		return nil, Metadata{}, err
+	}
+
+	if isDecompressed {
+		meta.Size = -1
+	}
+
+	return readCloser{
+		Reader: newMaxBytesReader(rdr, entry, o.maxBytes),
+		close: func() error {
+			return errors.Join(closeDecompressor(), file.Close())
+		},
+	}, meta, nil
+}
+
+func openHTTP(ctx context.Context, path string, o options) (io.ReadCloser, Metadata, error) {
+	if o.cache != nil || o.cacheOnly {
+		result, err := loadHTTP(ctx, path, o)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		return io.NopCloser(bytes.NewReader(result.data)), Metadata{
+			URL:         result.url,
+			ContentType: result.header.Get("Content-Type"),
+			Size:        int64(len(result.data)),
+			ModTime:     parseHTTPTime(result.header.Get("Last-Modified")),
+		}, nil
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if o.httpTimeout > 0 {
+		// the timeout applies until the stream is closed
+		ctx, cancel = context.WithTimeout(ctx, o.httpTimeout)
+	}
+
+	resp, err := withRetry(ctx, o, func() (*http.Response, error) {
+		return doHTTP(ctx, path, o, nil)
+	})
+	if err != nil {
+		cancel()
+
+		return nil, Metadata{}, err
+	}
+
+	meta := Metadata{
+		URL:         resp.Request.URL.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+		ModTime:     parseHTTPTime(resp.Header.Get("Last-Modified")),
+	}
+
+	rdr, closeDecompressor, isDecompressed, err := o.decompressReader(contextReader{ctx: ctx, Reader: resp.Body}, path, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		o.closeResponse(resp)
+		cancel()
+
+		return nil, Metadata{}, err
+	}
+
+	if isDecompressed {
+		meta.Size = -1
+	}
+
+	return readCloser{
+		Reader: newMaxBytesReader(rdr, path, o.maxBytes),
+		close: func() error {
+			defer cancel()
+
+			return errors.Join(closeDecompressor(), resp.Body.Close())
+		},
+	}, meta, nil
+}
+
+func contentTypeFromPath(name string) string {
+	switch formatFromPath(name) {
+	case formatJSON:
+		return "application/json"
+	case formatYAML:
+		return "application/yaml"
+	default:
+		return mime.TypeByExtension(filepath.Ext(name))
+	}
+}
+
+func parseHTTPTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
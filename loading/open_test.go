@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestOpen(t *testing.T) {
+	lastModified := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("with remote documents", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/spec.yaml", func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Header().Set("Content-Type", "application/yaml")
+			rw.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+			rw.Header().Set("Content-Length", strconv.Itoa(len(yamlPetStore)))
+			_, _ = rw.Write(yamlPetStore)
+		})
+		mux.Handle("/redirected", http.RedirectHandler("/spec.yaml", http.StatusFound))
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+
+		t.Run("should stream a remote document with its metadata", func(t *testing.T) {
+			rdr, meta, err := Open(ts.URL + "/spec.yaml")
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+
+			assert.EqualT(t, ts.URL+"/spec.yaml", meta.URL)
+			assert.EqualT(t, "application/yaml", meta.ContentType)
+			assert.EqualT(t, int64(len(yamlPetStore)), meta.Size)
+			assert.TrueT(t, lastModified.Equal(meta.ModTime))
+		})
+
+		t.Run("should report the URL after redirects", func(t *testing.T) {
+			rdr, meta, err := Open(ts.URL + "/redirected")
+			require.NoError(t, err)
+			require.NoError(t, rdr.Close())
+
+			assert.EqualT(t, ts.URL+"/spec.yaml", meta.URL)
+		})
+
+		t.Run("should fail on a remote error", func(t *testing.T) {
+			_, _, err := Open(ts.URL + "/unknown")
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should enforce the maximum size while streaming", func(t *testing.T) {
+			streaming := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				flusher, _ := rw.(http.Flusher)
+				for range 10 {
+					_, _ = rw.Write([]byte("chunk"))
+					flusher.Flush()
+				}
+			}))
+			defer streaming.Close()
+
+			rdr, meta, err := Open(streaming.URL, WithMaxBytes(12))
+			require.NoError(t, err)
+			defer rdr.Close()
+			assert.EqualT(t, int64(-1), meta.Size)
+
+			b, err := io.ReadAll(rdr)
+			requireMaxBytesError(t, err, 12)
+			assert.Len(t, b, 12)
+		})
+
+		t.Run("should serve a cached document", func(t *testing.T) {
+			cache := NewMemoryCache()
+			_, err := LoadFromFileOrHTTP(ts.URL+"/spec.yaml", WithCache(cache))
+			require.NoError(t, err)
+
+			rdr, meta, err := Open(ts.URL+"/spec.yaml", WithCache(cache), WithCacheOnly(true))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+			assert.EqualT(t, "application/yaml", meta.ContentType)
+			assert.TrueT(t, lastModified.Equal(meta.ModTime))
+		})
+
+		t.Run("should keep the timeout while streaming", func(t *testing.T) {
+			release := make(chan struct{})
+			slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte("start"))
+				rw.(http.Flusher).Flush()
+				<-release
+			}))
+			defer slow.Close()
+			defer close(release)
+
+			rdr, _, err := Open(slow.URL, WithTimeout(20*time.Millisecond))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			_, err = io.ReadAll(rdr)
+			require.Error(t, err)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	})
+
+	t.Run("with local documents", func(t *testing.T) {
+		mapfs := fstest.MapFS{
+			"folder/spec.json": &fstest.MapFile{Data: jsonPetStore, Mode: fs.ModePerm, ModTime: lastModified},
+		}
+
+		t.Run("should stream a document from a fs.FS", func(t *testing.T) {
+			rdr, meta, err := Open("file://folder/spec%2Ejson", WithFS(mapfs))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+
+			assert.EqualT(t, filepath.FromSlash("folder/spec.json"), meta.URL)
+			assert.EqualT(t, "application/json", meta.ContentType)
+			assert.EqualT(t, int64(len(jsonPetStore)), meta.Size)
+			assert.TrueT(t, lastModified.Equal(meta.ModTime))
+		})
+
+		t.Run("should stream a document from an embed.FS", func(t *testing.T) {
+			rdr, meta, err := Open("./fixtures/petstore_fixture.yaml", WithFS(embeddedFixtures))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, yamlPetStore, b)
+			assert.EqualT(t, "fixtures/petstore_fixture.yaml", meta.URL)
+			assert.EqualT(t, "application/yaml", meta.ContentType)
+		})
+
+		t.Run("should stream a document from the os file system", func(t *testing.T) {
+			rdr, meta, err := Open(filepath.Join("fixtures", "petstore_fixture.json"))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			b, err := io.ReadAll(rdr)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+			assert.EqualT(t, int64(len(jsonPetStore)), meta.Size)
+			assert.FalseT(t, meta.ModTime.IsZero())
+		})
+
+		t.Run("should fail on a missing file", func(t *testing.T) {
+			_, _, err := Open("folder/missing.json", WithFS(mapfs))
+			require.Error(t, err)
+			require.ErrorIs(t, err, fs.ErrNotExist)
+		})
+
+		t.Run("should reject a large file", func(t *testing.T) {
+			_, _, err := Open("folder/spec.json", WithFS(mapfs), WithMaxBytes(10))
+			requireMaxBytesError(t, err, 10)
+		})
+
+		t.Run("should abort reading when the context is cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			rdr, _, err := OpenContext(ctx, "folder/spec.json", WithFS(mapfs))
+			require.NoError(t, err)
+			defer rdr.Close()
+
+			cancel()
+			_, err = io.ReadAll(rdr)
+			require.ErrorIs(t, err, context.Canceled)
+		})
+	})
+
+	t.Run("should open a data URI", func(t *testing.T) {
+		rdr, meta, err := Open("data:application/json,{}")
+		require.NoError(t, err)
+		defer rdr.Close()
+
+		b, err := io.ReadAll(rdr)
+		require.NoError(t, err)
+		assert.EqualT(t, "{}", string(b))
+		assert.EqualT(t, "application/json", meta.ContentType)
+		assert.EqualT(t, int64(2), meta.Size)
+	})
+
+	t.Run("should not open an unsupported scheme", func(t *testing.T) {
+		_, _, err := Open("ftp://example.com/spec.json")
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+}
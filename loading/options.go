@@ -4,9 +4,15 @@
 package loading
 
 import (
+	"context"
 	"io/fs"
+	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,15 +26,110 @@ type (
 		basicAuthPassword string
 		customHeaders     map[string]string
 		client            *http.Client
+		accept            string
+	}
+
+	credentialsOptions struct {
+		credentials     CredentialsProvider
+		hostCredentials []hostCredentials
+		hostHeaders     []hostHeaders
+		netrc           func() ([]netrcMachine, error)
+	}
+
+	hostCredentials struct {
+		pattern  string
+		provider CredentialsProvider
+	}
+
+	hostHeaders struct {
+		pattern string
+		headers map[string]string
+	}
+
+	hostOptions struct {
+		allowedHosts         []string
+		deniedHosts          []string
+		blockPrivateNetworks bool
+		guardedClient        func() (*http.Client, error)
+		maxRedirects         int
+	}
+
+	rewriteOptions struct {
+		rewrites       []URLRewrite
+		strictRewrites bool
+		rewrittenFS    bool
+	}
+
+	loaderOptions struct {
+		concurrency     int
+		maxConnsPerHost int
+	}
+
+	watchOptions struct {
+		pollInterval time.Duration
+		debounce     time.Duration
+	}
+
+	integrityOptions struct {
+		integrity string
+		lockfile  *Lockfile
+	}
+
+	interpolationOptions struct {
+		interpolation func(string) (string, bool)
+	}
+
+	hookOptions struct {
+		hooks []Hooks
 	}
 
 	fileOptions struct {
 		fs fs.ReadFileFS
 	}
 
+	decompressOptions struct {
+		disableDecompression bool
+		customDecompressors  []Decompressor
+	}
+
+	archiveOptions struct {
+		archiveCache *ArchiveCache
+	}
+
+	limitOptions struct {
+		maxBytes int64
+	}
+
+	cacheOptions struct {
+		cache     Cache
+		cacheOnly bool
+	}
+
+	retryOptions struct {
+		retry RetryPolicy
+	}
+
+	schemeOptions struct {
+		schemes map[string]SchemeLoader
+	}
+
 	options struct {
 		httpOptions
+		credentialsOptions
+		hostOptions
+		rewriteOptions
+		loaderOptions
+		watchOptions
+		integrityOptions
+		hookOptions
+		interpolationOptions
 		fileOptions
+		schemeOptions
+		retryOptions
+		cacheOptions
+		limitOptions
+		decompressOptions
+		archiveOptions
 	}
 )
 
@@ -40,6 +141,142 @@ func (fo fileOptions) ReadFileFunc() func(string) ([]byte, error) {
 	return fo.fs.ReadFile
 }
 
+// openFile opens a local file, from the configured file system.
+func (fo fileOptions) openFile(name string) (fs.File, error) {
+	if fo.fs == nil {
+		return os.Open(name)
+	}
+
+	return fo.fs.Open(name)
+}
+
+// readFileContextFunc returns a file reader that aborts when the context is cancelled,
+// decompresses the content whenever needed, and enforces the maximum size of a document.
+func (o options) readFileContextFunc(ctx context.Context) func(string) ([]byte, error) {
+	if ctx.Done() == nil && o.maxBytes <= 0 {
+		if o.disableDecompression {
+			return o.ReadFileFunc()
+		}
+
+		readFile := o.ReadFileFunc()
+
+		return func(name string) ([]byte, error) {
+			data, err := readFile(name)
+			if err != nil {
+				return nil, err
+			}
+
+			return o.decompressBytes(data, name)
+		}
+	}
+
+	return func(name string) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		file, err := o.openFile(name)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+
+		if o.maxBytes > 0 {
+			if info, statErr := file.Stat(); statErr == nil && info.Size() > o.maxBytes {
+				return nil, &MaxBytesError{Path: name, Limit: o.maxBytes}
+			}
+		}
+
+		return o.readAllDecompressed(contextReader{ctx: ctx, Reader: file}, name, "")
+	}
+}
+
+// WithMaxBytes sets the maximum size in bytes of a loaded document.
+//
+// The limit applies to remote as well as to local documents. Loading a larger document
+// fails with a [*MaxBytesError].
+//
+// Whenever the size is known in advance (e.g. from the "Content-Length" header or from the file info),
+// the document is not read at all.
+//
+// By default, there is no limit. A zero or negative value disables the limit.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithDecompression enables or disables the transparent decompression of documents.
+//
+// Compressed documents are detected by their "Content-Encoding" (for remote documents), their extension or
+// their first bytes. See [Decompressor].
+//
+// Out of the box, gzip and bzip2 are supported. Other formats, e.g. zstd, may be added with [WithDecompressor].
+//
+// The maximum size set by [WithMaxBytes] applies to the decompressed content.
+//
+// By default, decompression is enabled.
+func WithDecompression(enabled bool) Option {
+	return func(o *options) {
+		o.disableDecompression = !enabled
+	}
+}
+
+// WithDecompressor adds support for a compression format, or overrides a built-in one.
+//
+// When the [Decompressor] declares the Encoding of a built-in format but leaves its
+// Extensions or Sniff function empty, those of the built-in format are used.
+//
+// For example, zstd is recognized as a compression format, but requires a decoder.
+// Using the package "github.com/klauspost/compress/zstd", one may provide one like so:
+//
+//	loading.WithDecompressor(loading.Decompressor{
+//		Encoding: "zstd",
+//		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+//			d, err := zstd.NewReader(r)
+//			if err != nil {
+//				return nil, err
+//			}
+//
+//			return d.IOReadCloser(), nil
+//		},
+//	})
+func WithDecompressor(decompressor Decompressor) Option {
+	return func(o *options) {
+		for _, builtin := range builtinDecompressors {
+			if !strings.EqualFold(builtin.Encoding, decompressor.Encoding) {
+				continue
+			}
+
+			if decompressor.Extensions == nil {
+				decompressor.Extensions = builtin.Extensions
+			}
+
+			if decompressor.Sniff == nil {
+				decompressor.Sniff = builtin.Sniff
+			}
+		}
+
+		o.customDecompressors = append(o.customDecompressors, decompressor)
+	}
+}
+
+// WithArchiveCache retains the archives loaded to access paths like "bundle.zip!/specs/pet.yaml",
+// so that every archive is fetched or read only once.
+//
+// By default, a [Loader] built with [NewLoader], or a [BatchLoader], retains the archives it loads,
+// for its own lifetime. Other functions, like [LoadFromFileOrHTTP], load the archive again on every call:
+// to load several entries, set an [ArchiveCache], reuse a [Loader], or use [OpenArchive] with [WithFS].
+//
+// The cache should only be shared by loads with the same options (see [ArchiveCache]).
+func WithArchiveCache(cache *ArchiveCache) Option {
+	return func(o *options) {
+		o.archiveCache = cache
+	}
+}
+
 // WithTimeout sets a timeout for the remote file loader.
 //
 // The default timeout is 30s.
@@ -50,6 +287,9 @@ func WithTimeout(timeout time.Duration) Option {
 }
 
 // WithBasicAuth sets a basic authentication scheme for the remote file loader.
+//
+// These credentials apply to all hosts, but are not sent after a redirect to another host.
+// Use [WithHostBasicAuth] or [WithNetrc] for credentials that are specific to some hosts.
 func WithBasicAuth(username, password string) Option {
 	return func(o *options) {
 		o.basicAuthUsername = username
@@ -57,7 +297,80 @@ func WithBasicAuth(username, password string) Option {
 	}
 }
 
+// WithCredentialsProvider sets a [CredentialsProvider] for the remote file loader.
+//
+// The headers returned by the provider take precedence over the ones set with [WithCustomHeaders].
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(o *options) {
+		o.credentials = provider
+	}
+}
+
+// WithBearerToken sets a static bearer token for the remote file loader,
+// sent as the header "Authorization: Bearer {token}".
+//
+// This is a shortcut for [WithCredentialsProvider]. Use a [CredentialsProvider] for tokens that expire.
+func WithBearerToken(token string) Option {
+	return WithCredentialsProvider(func(context.Context, *url.URL) (http.Header, error) {
+		return http.Header{"Authorization": []string{"Bearer " + token}}, nil
+	})
+}
+
+// WithHostCredentials sets a [CredentialsProvider] for the remote hosts that match a pattern.
+//
+// The pattern matches the host name without regard to case, and may contain wildcards with the syntax of [path.Match],
+// e.g. "*.example.com" matches all the subdomains of "example.com". A pattern with a port, e.g. "localhost:8080",
+// only matches this port.
+//
+// When several patterns match a host, the first one registered applies.
+//
+// Credentials for a host take precedence over the ones that apply to all hosts, set with [WithBasicAuth],
+// [WithBearerToken] or [WithCredentialsProvider]. They are set again after a redirect to another matching host.
+func WithHostCredentials(pattern string, provider CredentialsProvider) Option {
+	return func(o *options) {
+		o.hostCredentials = append(o.hostCredentials, hostCredentials{pattern: pattern, provider: provider})
+	}
+}
+
+// WithHostBasicAuth sets a basic authentication scheme for the remote hosts that match a pattern.
+//
+// See [WithHostCredentials].
+func WithHostBasicAuth(pattern, username, password string) Option {
+	return WithHostCredentials(pattern, basicAuthProvider(username, password))
+}
+
+// WithNetrc reads credentials for remote hosts from a .netrc file.
+//
+// If the path is empty, the file is located by the environment variable $NETRC, and defaults to "~/.netrc".
+// A missing default file is ignored.
+//
+// Entries for a machine apply as if set by [WithHostBasicAuth], after the ones set explicitly.
+// The "default" entry applies to all hosts, unless other credentials are set for all hosts.
+//
+// The file is read once, when first needed.
+func WithNetrc(path string) Option {
+	machines := sync.OnceValues(func() ([]netrcMachine, error) {
+		return readNetrc(path)
+	})
+
+	return func(o *options) {
+		o.netrc = machines
+	}
+}
+
+// WithHostCustomHeaders sets custom headers for the remote hosts that match a pattern.
+//
+// Patterns are described by [WithHostCredentials].
+func WithHostCustomHeaders(pattern string, headers map[string]string) Option {
+	return func(o *options) {
+		o.hostHeaders = append(o.hostHeaders, hostHeaders{pattern: pattern, headers: maps.Clone(headers)})
+	}
+}
+
 // WithCustomHeaders sets custom headers for the remote file loader.
+//
+// These headers are sent to all hosts, but not after a redirect to another host, since they may convey secrets
+// such as API keys. Use [WithHostCustomHeaders] for headers that are specific to some hosts.
 func WithCustomHeaders(headers map[string]string) Option {
 	return func(o *options) {
 		if o.customHeaders == nil {
@@ -70,6 +383,94 @@ func WithCustomHeaders(headers map[string]string) Option {
 	}
 }
 
+// WithAllowedHosts restricts the remote file loader to the hosts that match one of the given patterns.
+//
+// Patterns are described by [WithHostCredentials]. This option may be repeated to allow more hosts.
+//
+// The check applies to every request, including redirects. A refused host produces a [ForbiddenHostError].
+//
+// By default, all hosts are allowed.
+func WithAllowedHosts(patterns ...string) Option {
+	return func(o *options) {
+		o.allowedHosts = append(o.allowedHosts, patterns...)
+	}
+}
+
+// WithDeniedHosts prevents the remote file loader from accessing the hosts that match one of the given patterns.
+//
+// Patterns are described by [WithHostCredentials]. Denied hosts take precedence over allowed hosts.
+//
+// The check applies to every request, including redirects. A refused host produces a [ForbiddenHostError].
+func WithDeniedHosts(patterns ...string) Option {
+	return func(o *options) {
+		o.deniedHosts = append(o.deniedHosts, patterns...)
+	}
+}
+
+// WithBlockPrivateNetworks prevents the remote file loader from connecting to private, loopback
+// or link-local addresses, e.g. "localhost", "10.0.0.1" or "169.254.169.254".
+//
+// This is intended for services that load documents from untrusted URLs (i.e. against server-side request forgery).
+//
+// The check occurs when connecting, after the host name is resolved, so it cannot be bypassed with DNS tricks.
+// A refused address produces a [ForbiddenHostError].
+//
+// This requires the HTTP client to use an [http.Transport]. Its custom dial functions are not used,
+// and neither is its proxy: requests are sent directly to the remote host, even if a proxy is set
+// on the transport or with the environment variables HTTPS_PROXY and HTTP_PROXY.
+// The connections are reused by all the loads with the same options, e.g. by a [Loader].
+func WithBlockPrivateNetworks(enabled bool) Option {
+	return func(o *options) {
+		o.blockPrivateNetworks = enabled
+	}
+}
+
+// WithMaxRedirects sets the maximum number of redirects followed by the remote file loader.
+//
+// A value of 0 disables redirects. The default is 10.
+func WithMaxRedirects(n int) Option {
+	return func(o *options) {
+		o.maxRedirects = max(n, 0)
+	}
+}
+
+// WithRetry sets a retry policy for the remote file loader.
+//
+// Unset fields of the [RetryPolicy] take their default values.
+//
+// By default, remote loads are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy.withDefaults()
+	}
+}
+
+// WithCache sets a cache for remote documents.
+//
+// Cached documents are revalidated with a conditional request ("If-None-Match" or "If-Modified-Since"),
+// unless the server indicated with "Cache-Control: max-age" that they remain fresh for some time.
+//
+// Responses marked with "Cache-Control: no-store" are not cached.
+//
+// See [NewMemoryCache] and [NewDirCache].
+//
+// By default, there is no cache.
+func WithCache(cache Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithCacheOnly serves remote documents only from the cache set with [WithCache], and never
+// accesses the network.
+//
+// This is intended for offline (e.g. air-gapped) builds. A document missing from the cache produces an error.
+func WithCacheOnly(enabled bool) Option {
+	return func(o *options) {
+		o.cacheOnly = enabled
+	}
+}
+
 // WithHTTPClient overrides the default HTTP client used to fetch a remote file.
 //
 // By default, [http.DefaultClient] is used.
@@ -98,6 +499,150 @@ func WithFS(filesystem fs.FS) Option {
 	}
 }
 
+// WithURLRewrites maps URL prefixes to local directories, file systems or other base URLs.
+//
+// Rewrites apply before the load strategy is determined (see [LoadStrategy]). When several prefixes match,
+// the longest one applies. Rewritten paths are not rewritten again.
+//
+// This is intended for offline or hermetic builds, which cannot access remote documents.
+// See also [WithStrictURLRewrites].
+func WithURLRewrites(rewrites ...URLRewrite) Option {
+	return func(o *options) {
+		o.rewrites = append(o.rewrites, rewrites...)
+	}
+}
+
+// WithStrictURLRewrites fails to load any remote URL that is not rewritten by [WithURLRewrites].
+func WithStrictURLRewrites(enabled bool) Option {
+	return func(o *options) {
+		o.strictRewrites = enabled
+	}
+}
+
+// WithConcurrency sets the maximum number of documents loaded concurrently by a [BatchLoader].
+//
+// The default is 8. This option has no effect outside of a [BatchLoader].
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.concurrency = n
+		}
+	}
+}
+
+// WithMaxConnsPerHost limits the number of connections opened by a [BatchLoader] to every remote host.
+//
+// This requires the HTTP client to use an [http.Transport]. The default is no limit.
+// This option has no effect outside of a [BatchLoader].
+func WithMaxConnsPerHost(n int) Option {
+	return func(o *options) {
+		o.maxConnsPerHost = max(n, 0)
+	}
+}
+
+// WithPollInterval sets the interval at which [Watch] checks a document for changes.
+//
+// The default is 1s. This option has no effect outside of [Watch].
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+// WithDebounce sets how long a document must remain unchanged before [Watch] emits a new version,
+// e.g. so that an editor may save a file in several steps.
+//
+// The default is 100ms. A zero value emits a new version as soon as a change is detected.
+// This option has no effect outside of [Watch].
+func WithDebounce(debounce time.Duration) Option {
+	return func(o *options) {
+		if debounce >= 0 {
+			o.debounce = debounce
+		}
+	}
+}
+
+// WithIntegrity verifies that a loaded document matches some integrity metadata, with the format
+// defined by the W3C subresource integrity specification, e.g. "sha256-{base64 digest}".
+//
+// Several space-separated digests may be provided: the document must match one of the digests with the
+// strongest algorithm. Supported algorithms are sha256, sha384 and sha512. See also [Integrity].
+//
+// The integrity may alternatively be provided as a URL fragment, e.g. "https://example.com/pet.yaml#integrity=sha256-...",
+// which takes precedence over this option.
+//
+// The integrity applies to the loaded document, after decompression. A mismatch produces an [IntegrityError].
+func WithIntegrity(integrity string) Option {
+	return func(o *options) {
+		o.integrity = integrity
+	}
+}
+
+// WithLockfile records the integrity of every loaded document in a [Lockfile],
+// and verifies the documents that are already recorded.
+func WithLockfile(lock *Lockfile) Option {
+	return func(o *options) {
+		o.lockfile = lock
+	}
+}
+
+// WithHooks installs [Hooks] to observe the activity of the loader.
+//
+// This option may be repeated: all the hooks installed are called, in order.
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks)
+	}
+}
+
+// WithInterpolation expands placeholders like "${SERVICE_HOST}" in the string values of documents,
+// e.g. with [os.LookupEnv] as the lookup function.
+//
+// Interpolation applies to parsed documents only (see [Doc], [JSONDoc], [YAMLDoc], [YAMLData] and [YAMLDocs]):
+// keys and raw bytes are never altered, e.g. by [LoadFromFileOrHTTP].
+//
+// The syntax of placeholders is:
+//   - "${VAR}" is replaced by the value of the variable VAR
+//   - "${VAR:-default}" is replaced by the value of VAR, or "default" if VAR is undefined or empty
+//   - "$$" is replaced by a single "$", e.g. "$${VAR}" yields "${VAR}" verbatim
+//
+// A "$" that doesn't start a placeholder is left as is.
+//
+// If some variables are undefined and have no default, the load fails with an [InterpolationError],
+// which lists all of them.
+func WithInterpolation(lookup func(string) (string, bool)) Option {
+	return func(o *options) {
+		o.interpolation = lookup
+	}
+}
+
+// WithLogger logs the activity of the loader with a [slog.Logger].
+//
+// This is a shortcut for [WithHooks] with [SlogHooks].
+func WithLogger(logger *slog.Logger) Option {
+	return WithHooks(SlogHooks(logger))
+}
+
+// WithSchemeLoader registers a loader for URIs with a given scheme.
+//
+// The scheme is matched without regard to case, e.g. "mem" matches "mem://doc" and "MEM://doc".
+//
+// A registered loader takes precedence over the built-in handling of the schemes "http", "https", "file" and "data".
+//
+// For example, this may be used to serve URIs such as "embed://..." or "mem://..." or some
+// company-specific scheme.
+func WithSchemeLoader(scheme string, loader SchemeLoader) Option {
+	return func(o *options) {
+		if o.schemes == nil {
+			o.schemes = make(map[string]SchemeLoader)
+		}
+
+		o.schemes[strings.ToLower(strings.TrimSuffix(scheme, ":"))] = loader
+	}
+}
+
 type readFileFS struct {
 	fs.FS
 }
@@ -115,11 +660,21 @@ func optionsWithDefaults(opts []Option) options {
 			httpTimeout: defaultTimeout,
 			client:      http.DefaultClient,
 		},
+		hostOptions: hostOptions{
+			maxRedirects: defaultMaxRedirects,
+		},
+		loaderOptions: loaderOptions{
+			concurrency: defaultConcurrency,
+		},
+		watchOptions: watchOptions{
+			pollInterval: defaultPollInterval,
+			debounce:     defaultDebounce,
+		},
 	}
 
 	for _, apply := range opts {
 		apply(&o)
 	}
 
-	return o
+	return o.withGuardedClient()
 }
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// contextReader is an [io.Reader] that stops reading as soon as its context is done.
+//
+// The context is checked before every call to the underlying reader, so a slow
+// stream is interrupted at the next chunk. Read errors caused by the context being done
+// report the context error.
+type contextReader struct {
+	ctx context.Context //nolint:containedctx // the reader is short-lived and bound to a single load operation
+	io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.Reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			// e.g. a connection closed because the context is done: report the root cause
+			return n, errors.Join(err, ctxErr)
+		}
+	}
+
+	return n, err
+}
+
+// readAllLimited reads all the content from a reader, up to limit bytes.
+//
+// A zero or negative limit means no limit.
+func readAllLimited(r io.Reader, path string, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, &MaxBytesError{Path: path, Limit: limit}
+	}
+
+	return data, nil
+}
+
+// checkMaxBytes verifies that some already loaded content does not exceed the limit.
+func checkMaxBytes(data []byte, path string, limit int64) ([]byte, error) {
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, &MaxBytesError{Path: path, Limit: limit}
+	}
+
+	return data, nil
+}
+
+// maxBytesReader is an [io.Reader] that fails with a [*MaxBytesError] when reading past a limit.
+type maxBytesReader struct {
+	io.Reader
+
+	path      string
+	limit     int64
+	remaining int64
+}
+
+func newMaxBytesReader(r io.Reader, path string, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+
+	return &maxBytesReader{Reader: r, path: path, limit: limit, remaining: limit}
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, &MaxBytesError{Path: r.path, Limit: r.limit}
+	}
+
+	// read one more byte than allowed, to detect that the limit is exceeded
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.Reader.Read(p)
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+		r.remaining = -1
+
+		return n, &MaxBytesError{Path: r.path, Limit: r.limit}
+	}
+	r.remaining -= int64(n)
+
+	return n, err
+}
+
+// readCloser combines a reader with a function to close it.
+type readCloser struct {
+	io.Reader
+
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestWithMaxBytes(t *testing.T) {
+	const limit = 16
+
+	small := []byte("small content")
+	large := bytes.Repeat([]byte("x"), 4*limit)
+
+	t.Run("with remote documents", func(t *testing.T) {
+		t.Run("should load a document within the limit", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write(small)
+			}))
+			defer ts.Close()
+
+			b, err := LoadFromFileOrHTTP(ts.URL, WithMaxBytes(limit))
+			require.NoError(t, err)
+			assert.Equal(t, small, b)
+		})
+
+		t.Run("should reject a document with a large Content-Length", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write(large)
+			}))
+			defer ts.Close()
+
+			_, err := LoadFromFileOrHTTP(ts.URL, WithMaxBytes(limit))
+			requireMaxBytesError(t, err, limit)
+		})
+
+		t.Run("should stop reading a streamed document past the limit", func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				flusher, _ := rw.(http.Flusher)
+				for range 100 {
+					_, _ = rw.Write([]byte("chunk"))
+					flusher.Flush() // no Content-Length is sent
+				}
+			}))
+			defer ts.Close()
+
+			_, err := LoadFromFileOrHTTP(ts.URL, WithMaxBytes(limit))
+			requireMaxBytesError(t, err, limit)
+		})
+
+		t.Run("should not retry when the limit is exceeded", func(t *testing.T) {
+			var attempts int
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				attempts++
+				_, _ = rw.Write(large)
+			}))
+			defer ts.Close()
+
+			_, err := LoadFromFileOrHTTP(ts.URL, WithMaxBytes(limit), WithRetry(RetryPolicy{MaxAttempts: 3}))
+			requireMaxBytesError(t, err, limit)
+			assert.EqualT(t, 1, attempts)
+		})
+	})
+
+	t.Run("with local documents", func(t *testing.T) {
+		mapfs := fstest.MapFS{
+			"small": &fstest.MapFile{Data: small, Mode: fs.ModePerm},
+			"large": &fstest.MapFile{Data: large, Mode: fs.ModePerm},
+		}
+
+		t.Run("should load a document within the limit", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("small", WithFS(mapfs), WithMaxBytes(limit))
+			require.NoError(t, err)
+			assert.Equal(t, small, b)
+		})
+
+		t.Run("should reject a large document from a fs.FS", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("large", WithFS(mapfs), WithMaxBytes(limit))
+			requireMaxBytesError(t, err, limit)
+		})
+
+		t.Run("should reject a large document from the os file system", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.yaml", WithMaxBytes(limit))
+			requireMaxBytesError(t, err, limit)
+		})
+
+		t.Run("should reject a large document with unknown size", func(t *testing.T) {
+			slow := slowFS{MapFS: mapfs} // reports the size, but we check the streamed read anyway
+			_, err := readAllLimited(slowFile{File: mustOpen(t, slow, "large")}, "large", limit)
+			requireMaxBytesError(t, err, limit)
+		})
+	})
+
+	t.Run("with loaders supplied to LoadStrategy", func(t *testing.T) {
+		load := func(string) ([]byte, error) { return large, nil }
+
+		t.Run("should reject a large local document", func(t *testing.T) {
+			_, err := LoadStrategy("large", load, nil, WithMaxBytes(limit))("large")
+			requireMaxBytesError(t, err, limit)
+		})
+
+		t.Run("should reject a large remote document", func(t *testing.T) {
+			const url = "https://example.com/large"
+
+			_, err := LoadStrategy(url, nil, load, WithMaxBytes(limit))(url)
+			requireMaxBytesError(t, err, limit)
+		})
+	})
+
+	t.Run("should reject a large data URI", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP("data:,"+string(large), WithMaxBytes(limit))
+		requireMaxBytesError(t, err, limit)
+	})
+
+	t.Run("should not limit when disabled", func(t *testing.T) {
+		b, err := readAllLimited(strings.NewReader(string(large)), "large", 0)
+		require.NoError(t, err)
+		assert.Len(t, b, len(large))
+	})
+}
+
+func requireMaxBytesError(t *testing.T, err error, limit int64) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrLoader)
+
+	var maxBytesErr *MaxBytesError
+	require.TrueT(t, errors.As(err, &maxBytesErr))
+	assert.EqualT(t, limit, maxBytesErr.Limit)
+	assert.StringContainsT(t, maxBytesErr.Error(), "exceeds the maximum size")
+}
+
+func mustOpen(t *testing.T, fsys fs.FS, name string) fs.File {
+	t.Helper()
+
+	f, err := fsys.Open(name)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	return f
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+// Package refs resolves JSON references ("$ref") in documents loaded with package [loading].
+//
+// A reference is an object like {"$ref": "other.yaml#/definitions/pet"}: the URI is resolved against the
+// location of the referring document, and the fragment is a JSON Pointer (RFC 6901) into the target document.
+//
+// A [Resolver] walks a root document and loads every referenced document once, e.g. to:
+//   - produce a single document, with all references replaced by their targets (see [Resolver.Inline])
+//   - collect all the documents involved, keyed by canonical URI (see [Resolver.Documents])
+//
+// Documents are represented as [jsonutils.JSONMapSlice], so the order of keys is maintained.
+//
+// [loading]: https://pkg.go.dev/github.com/go-openapi/swag/loading
+package refs
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+type refError string
+
+const (
+	// ErrRef is an error raised when resolving JSON references
+	ErrRef refError = "ref error"
+)
+
+func (e refError) Error() string {
+	return string(e)
+}
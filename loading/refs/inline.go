@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/go-openapi/swag/jsonutils"
+)
+
+// location designates a value in a document: the canonical URI of the document, and a JSON Pointer.
+type location struct {
+	doc    string
+	tokens []string
+}
+
+func (l location) child(token string) location {
+	return location{doc: l.doc, tokens: append(slices.Clip(l.tokens), token)}
+}
+
+// contains tells if a location is the same as, or an ancestor of another location.
+func (l location) contains(other location) bool {
+	return l.doc == other.doc && len(l.tokens) <= len(other.tokens) && slices.Equal(l.tokens, other.tokens[:len(l.tokens)])
+}
+
+// inliner replaces references by their targets.
+type inliner struct {
+	resolver *Resolver
+	root     string
+}
+
+// value inlines the references in a value, located at here.
+//
+// The parents are the locations of the references that led to this value.
+func (in *inliner) value(ctx context.Context, value any, base *url.URL, here location, parents []location) (any, error) {
+	switch node := value.(type) {
+	case jsonutils.JSONMapSlice:
+		if ref, isRef := refOf(node); isRef {
+			target, err := resolveURI(base, ref)
+			if err != nil {
+				return nil, err
+			}
+
+			return in.ref(ctx, target, here, parents)
+		}
+
+		inlined := make(jsonutils.JSONMapSlice, 0, len(node))
+		for _, item := range node {
+			child, err := in.value(ctx, item.Value, base, here.child(item.Key), parents)
+			if err != nil {
+				return nil, err
+			}
+
+			inlined = append(inlined, jsonutils.JSONMapItem{Key: item.Key, Value: child})
+		}
+
+		return inlined, nil
+	case []any:
+		inlined := make([]any, 0, len(node))
+		for i, item := range node {
+			child, err := in.value(ctx, item, base, here.child(strconv.Itoa(i)), parents)
+			if err != nil {
+				return nil, err
+			}
+
+			inlined = append(inlined, child)
+		}
+
+		return inlined, nil
+	default:
+		return value, nil
+	}
+}
+
+// ref inlines the target of a reference, found at here.
+//
+// A target that contains the reference, or one of the references that led to it, forms a cycle:
+// the reference is left in place.
+func (in *inliner) ref(ctx context.Context, target *url.URL, here location, parents []location) (any, error) {
+	tokens, err := parsePointer(target.Fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	at := location{doc: documentKey(target), tokens: tokens}
+	if at.contains(here) || slices.ContainsFunc(parents, at.contains) {
+		return jsonutils.JSONMapSlice{{Key: refKey, Value: in.cyclicRef(at)}}, nil
+	}
+
+	doc, err := in.resolver.document(ctx, at.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := resolvePointer(doc, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("in document %q: %w", at.doc, err)
+	}
+
+	return in.value(ctx, value, target, at, append(slices.Clip(parents), here))
+}
+
+// cyclicRef is the URI of a reference that is left in place.
+func (in *inliner) cyclicRef(at location) string {
+	fragment := (&url.URL{Fragment: formatPointer(at.tokens)}).EscapedFragment()
+	if at.doc == in.root {
+		return "#" + fragment
+	}
+
+	return at.doc + "#" + fragment
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/swag/jsonutils"
+)
+
+var (
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+)
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+//
+// The empty pointer designates the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: should start with a slash: %w", pointer, ErrRef)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// formatPointer builds a JSON Pointer from its reference tokens.
+func formatPointer(tokens []string) string {
+	var b strings.Builder
+
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+
+	return b.String()
+}
+
+// resolvePointer yields the value designated by some reference tokens.
+func resolvePointer(value any, tokens []string) (any, error) {
+	for i, token := range tokens {
+		switch node := value.(type) {
+		case jsonutils.JSONMapSlice:
+			found := false
+			for _, item := range node {
+				if item.Key == token {
+					value = item.Value
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("JSON pointer %q not found: no key %q: %w", formatPointer(tokens), token, ErrRef)
+			}
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) || (token != "0" && strings.HasPrefix(token, "0")) {
+				return nil, fmt.Errorf("JSON pointer %q not found: invalid index %q: %w", formatPointer(tokens), token, ErrRef)
+			}
+
+			value = node[index]
+		default:
+			return nil, fmt.Errorf("JSON pointer %q not found: %q is not a container: %w", formatPointer(tokens), formatPointer(tokens[:i]), ErrRef)
+		}
+	}
+
+	return value, nil
+}
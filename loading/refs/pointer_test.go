@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+import (
+	"testing"
+
+	"github.com/go-openapi/swag/jsonutils"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestPointer(t *testing.T) {
+	doc := jsonutils.JSONMapSlice{
+		{Key: "a/b", Value: jsonutils.JSONMapSlice{{Key: "m~n", Value: "escaped"}}},
+		{Key: "list", Value: []any{"zero", "one"}},
+		{Key: "", Value: "empty key"},
+	}
+
+	t.Run("should parse and format pointers", func(t *testing.T) {
+		tokens, err := parsePointer("/a~1b/m~0n")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a/b", "m~n"}, tokens)
+		assert.EqualT(t, "/a~1b/m~0n", formatPointer(tokens))
+
+		tokens, err = parsePointer("")
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+
+		_, err = parsePointer("a/b")
+		require.ErrorIs(t, err, ErrRef)
+	})
+
+	t.Run("should resolve pointers", func(t *testing.T) {
+		for pointer, expected := range map[string]any{
+			"/a~1b/m~0n": "escaped",
+			"/list/1":    "one",
+			"/":          "empty key",
+		} {
+			tokens, err := parsePointer(pointer)
+			require.NoError(t, err)
+
+			value, err := resolvePointer(doc, tokens)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+
+		value, err := resolvePointer(doc, nil)
+		require.NoError(t, err)
+		assert.Equal(t, doc, value)
+	})
+
+	t.Run("should NOT resolve invalid pointers", func(t *testing.T) {
+		for _, pointer := range []string{"/nowhere", "/list/2", "/list/-1", "/list/01", "/list/x", "/list/0/x"} {
+			tokens, err := parsePointer(pointer)
+			require.NoError(t, err)
+
+			_, err = resolvePointer(doc, tokens)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrRef)
+		}
+	})
+}
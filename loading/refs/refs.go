@@ -0,0 +1,367 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/swag/jsonutils"
+	"github.com/go-openapi/swag/jsonutils/adapters/ifaces"
+	"github.com/go-openapi/swag/loading"
+)
+
+// refKey is the key of a JSON reference object.
+const refKey = "$ref"
+
+// Resolver resolves the JSON references in documents loaded from files or remote urls.
+//
+// Every document is loaded only once by a [Resolver], and retained for subsequent resolutions.
+//
+// A [Resolver] is safe for concurrent use.
+type Resolver struct {
+	options []loading.Option
+
+	mx   sync.Mutex
+	docs map[string]jsonutils.JSONMapSlice
+}
+
+// NewResolver builds a [Resolver]. Documents are loaded with [loading.DocContext] and the given options.
+//
+// Archives are retained by the [Resolver], so that the documents bundled in an archive, e.g. "bundle.zip!/specs/root.yaml",
+// are loaded from an archive fetched or read only once (see [loading.WithArchiveCache]).
+func NewResolver(opts ...loading.Option) *Resolver {
+	return &Resolver{
+		options: append([]loading.Option{loading.WithArchiveCache(loading.NewArchiveCache())}, opts...),
+		docs:    make(map[string]jsonutils.JSONMapSlice),
+	}
+}
+
+// Inline loads the document at the root path or URL, and replaces all references by their targets,
+// recursively.
+//
+// References that form a cycle cannot be inlined: they are left in place as {"$ref": "..."}, with a URI
+// that is either a fragment like "#/definitions/node" for the root document, or the canonical URI of
+// the target (see [Resolver.Documents]).
+//
+// If the root has a fragment with a JSON Pointer, the inlined document is the value designated by this pointer.
+// Other fragments, like "#integrity=sha256-...", are passed to the loader along with the root (see [Resolver.Documents]).
+func (r *Resolver) Inline(ctx context.Context, root string) (jsonutils.JSONMapSlice, error) {
+	rootURL, err := r.root(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &inliner{resolver: r, root: documentKey(rootURL)}
+
+	value, err := in.ref(ctx, rootURL, location{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, isObject := value.(jsonutils.JSONMapSlice)
+	if !isObject {
+		return nil, fmt.Errorf("the value at %q is not a JSON object: %w", root, ErrRef)
+	}
+
+	return doc, nil
+}
+
+// Documents loads the document at the root path or URL, and all the documents it references, recursively.
+//
+// Documents are keyed by canonical URI: the location of the document, resolved against the referring
+// document, without fragment. The root document is keyed by its own path or URL, e.g. "C:/specs/root.yaml"
+// for a windows path like `C:\specs\root.yaml`.
+//
+// All references are checked: the JSON Pointer in their fragment must designate a value in the target document.
+//
+// A fragment of the root which is not a JSON Pointer, like "#integrity=sha256-...", is passed to the loader
+// along with the root: the root document is then loaded again, even if retained by the [Resolver].
+func (r *Resolver) Documents(ctx context.Context, root string) (map[string]jsonutils.JSONMapSlice, error) {
+	rootURL, err := r.root(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]jsonutils.JSONMapSlice)
+	queue := []*url.URL{rootURL}
+	var targets []*url.URL
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		key := documentKey(current)
+		if _, loaded := docs[key]; loaded {
+			continue
+		}
+
+		doc, err := r.document(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		docs[key] = doc
+
+		err = walkRefs(doc, func(ref string) error {
+			target, err := resolveURI(current, ref)
+			if err != nil {
+				return err
+			}
+
+			targets = append(targets, target)
+			queue = append(queue, target)
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("in document %q: %w", key, err)
+		}
+	}
+
+	for _, target := range targets {
+		tokens, err := parsePointer(target.Fragment)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = resolvePointer(docs[documentKey(target)], tokens); err != nil {
+			return nil, fmt.Errorf("in document %q: %w", documentKey(target), err)
+		}
+	}
+
+	return docs, nil
+}
+
+// Inline loads the document at the root path or URL, and replaces all references by their targets.
+//
+// See [Resolver.Inline].
+func Inline(ctx context.Context, root string, opts ...loading.Option) (jsonutils.JSONMapSlice, error) {
+	return NewResolver(opts...).Inline(ctx, root)
+}
+
+// Documents loads the document at the root path or URL, and all the documents it references.
+//
+// See [Resolver.Documents].
+func Documents(ctx context.Context, root string, opts ...loading.Option) (map[string]jsonutils.JSONMapSlice, error) {
+	return NewResolver(opts...).Documents(ctx, root)
+}
+
+// root parses the root of a resolution.
+//
+// A fragment which is not a JSON Pointer is meant for the loader, e.g. "#integrity=sha256-...":
+// the root document is loaded with this fragment, which is removed from the returned URI.
+func (r *Resolver) root(ctx context.Context, root string) (*url.URL, error) {
+	rootURL, err := parseURI(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %q: %w: %w", root, err, ErrRef)
+	}
+
+	if rootURL.Fragment == "" || strings.HasPrefix(rootURL.Fragment, "/") {
+		return rootURL, nil
+	}
+
+	fragment := rootURL.EscapedFragment()
+	rootURL.Fragment = ""
+	rootURL.RawFragment = ""
+
+	key := documentKey(rootURL)
+	if _, err = r.load(ctx, key, key+"#"+fragment); err != nil {
+		return nil, err
+	}
+
+	return rootURL, nil
+}
+
+// document loads a document by its canonical URI, or retrieves it if already loaded.
+func (r *Resolver) document(ctx context.Context, key string) (jsonutils.JSONMapSlice, error) {
+	r.mx.Lock()
+	doc, found := r.docs[key]
+	r.mx.Unlock()
+
+	if found {
+		return doc, nil
+	}
+
+	return r.load(ctx, key, key)
+}
+
+// load loads the document with a canonical URI from a path, and retains it.
+func (r *Resolver) load(ctx context.Context, key, pth string) (jsonutils.JSONMapSlice, error) {
+	data, err := loading.DocContext(ctx, pth, r.options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load document %q: %w: %w", key, err, ErrRef)
+	}
+
+	var doc jsonutils.JSONMapSlice
+	if err = jsonutils.ReadJSON(data, &doc); err != nil {
+		return nil, fmt.Errorf("document %q is not a JSON object: %w: %w", key, err, ErrRef)
+	}
+
+	doc, _ = normalize(doc).(jsonutils.JSONMapSlice)
+
+	r.mx.Lock()
+	r.docs[key] = doc
+	r.mx.Unlock()
+
+	return doc, nil
+}
+
+// normalize represents all the objects in a value as [jsonutils.JSONMapSlice].
+func normalize(value any) any {
+	switch node := value.(type) {
+	case ifaces.Ordered:
+		doc := make(jsonutils.JSONMapSlice, 0)
+		for key, item := range node.OrderedItems() {
+			doc = append(doc, jsonutils.JSONMapItem{Key: key, Value: normalize(item)})
+		}
+
+		return doc
+	case map[string]any:
+		doc := make(jsonutils.JSONMapSlice, 0, len(node))
+		for _, key := range slices.Sorted(maps.Keys(node)) {
+			doc = append(doc, jsonutils.JSONMapItem{Key: key, Value: normalize(node[key])})
+		}
+
+		return doc
+	case []any:
+		for i, item := range node {
+			node[i] = normalize(item)
+		}
+
+		return node
+	default:
+		return value
+	}
+}
+
+// refOf tells if an object is a JSON reference, and returns its URI.
+func refOf(node jsonutils.JSONMapSlice) (string, bool) {
+	for _, item := range node {
+		if item.Key == refKey {
+			ref, isString := item.Value.(string)
+
+			return ref, isString
+		}
+	}
+
+	return "", false
+}
+
+// walkRefs calls fn for every JSON reference in a value.
+func walkRefs(value any, fn func(string) error) error {
+	switch node := value.(type) {
+	case jsonutils.JSONMapSlice:
+		if ref, isRef := refOf(node); isRef {
+			return fn(ref)
+		}
+
+		for _, item := range node {
+			if err := walkRefs(item.Value, fn); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range node {
+			if err := walkRefs(item, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveURI resolves the URI of a reference against the URI of the referring document.
+//
+// A local path without scheme is resolved as a relative path, so that it remains relative.
+// A windows path with a drive letter is absolute.
+func resolveURI(base *url.URL, ref string) (*url.URL, error) {
+	u, err := parseURI(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w: %w", ref, err, ErrRef)
+	}
+
+	if u.IsAbs() || base.IsAbs() {
+		return base.ResolveReference(u), nil
+	}
+
+	resolved := *u
+	switch {
+	case u.Path == "":
+		resolved.Path = base.Path
+	case isDrivePath(u.Path):
+		resolved.Path = path.Clean(u.Path)
+	case !path.IsAbs(u.Path):
+		resolved.Path = path.Join(path.Dir(base.Path), u.Path)
+	default:
+		resolved.Path = path.Clean(u.Path)
+	}
+	resolved.RawPath = ""
+
+	return &resolved, nil
+}
+
+// documentKey is the canonical URI of a document: its location without fragment,
+// with a lower-cased scheme and host.
+func documentKey(u *url.URL) string {
+	key := *u
+	key.Scheme = strings.ToLower(key.Scheme)
+	key.Host = strings.ToLower(key.Host)
+	key.Fragment = ""
+	key.RawFragment = ""
+
+	if !key.IsAbs() {
+		key.Path = path.Clean(key.Path)
+		key.RawPath = ""
+
+		// a path with a drive letter is not prefixed with "./"
+		return strings.TrimPrefix(key.String(), "./")
+	}
+
+	return key.String()
+}
+
+// parseURI parses the URI of a document or a reference.
+//
+// A windows path with a drive letter, e.g. `C:\specs\root.yaml`, is not a URI with the scheme "c":
+// it is parsed as a local path with forward slashes, e.g. "C:/specs/root.yaml".
+func parseURI(uri string) (*url.URL, error) {
+	if !isDrivePath(uri) {
+		return url.Parse(uri)
+	}
+
+	pth, fragment, hasFragment := strings.Cut(uri, "#")
+
+	u := &url.URL{}
+	if hasFragment {
+		var err error
+		if u, err = url.Parse("#" + fragment); err != nil {
+			return nil, err
+		}
+	}
+	u.Path = strings.ReplaceAll(pth, `\`, "/")
+
+	return u, nil
+}
+
+// isDrivePath tells if a path starts with a windows drive letter, e.g. `C:\` or "C:/".
+//
+// Like in package [loading], a single-letter scheme is a drive letter.
+func isDrivePath(pth string) bool {
+	if len(pth) < 2 || pth[1] != ':' {
+		return false
+	}
+
+	if c := pth[0]; (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+		return false
+	}
+
+	return len(pth) == 2 || pth[2] == '/' || pth[2] == '\\'
+}
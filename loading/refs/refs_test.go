@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package refs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/swag/jsonutils"
+	"github.com/go-openapi/swag/loading"
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+var specFS = fstest.MapFS{
+	"root.json": &fstest.MapFile{Data: []byte(`{
+  "info": {"$ref": "common.yaml#/info"},
+  "definitions": {
+    "pet": {"$ref": "models/pet.json"},
+    "pets": {"type": "array", "items": {"$ref": "#/definitions/pet"}},
+    "node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}}
+  }
+}`)},
+	"common.yaml": &fstest.MapFile{Data: []byte(`info:
+  title: pet store
+tag:
+  type: string
+`)},
+	"models/pet.json": &fstest.MapFile{Data: []byte(`{"type": "object", "properties": {"tag": {"$ref": "../common.yaml#/tag"}}}`)},
+	"cycle/a.json":    &fstest.MapFile{Data: []byte(`{"b": {"$ref": "b.json"}}`)},
+	"cycle/b.json":    &fstest.MapFile{Data: []byte(`{"a": {"$ref": "a.json"}}`)},
+	"cycle/root.json": &fstest.MapFile{Data: []byte(`{"a": {"$ref": "a.json"}}`)},
+	"broken.json":     &fstest.MapFile{Data: []byte(`{"missing": {"$ref": "common.yaml#/nowhere"}}`)},
+	"dangling.json":   &fstest.MapFile{Data: []byte(`{"missing": {"$ref": "nowhere.json"}}`)},
+	"array.json":      &fstest.MapFile{Data: []byte(`[1, 2]`)},
+}
+
+func TestInline(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should inline all references", func(t *testing.T) {
+		doc, err := Inline(ctx, "root.json", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{
+  "info": {"title": "pet store"},
+  "definitions": {
+    "pet": {"type": "object", "properties": {"tag": {"type": "string"}}},
+    "pets": {"type": "array", "items": {"type": "object", "properties": {"tag": {"type": "string"}}}},
+    "node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}}
+  }
+}`, mustJSON(t, doc))
+	})
+
+	t.Run("should maintain the order of keys", func(t *testing.T) {
+		doc, err := Inline(ctx, "root.json", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		keys := make([]string, 0, len(doc))
+		for _, item := range doc {
+			keys = append(keys, item.Key)
+		}
+		assert.Equal(t, []string{"info", "definitions"}, keys)
+	})
+
+	t.Run("should inline the value designated by a fragment", func(t *testing.T) {
+		doc, err := Inline(ctx, "root.json#/definitions/pets", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{"type": "array", "items": {"type": "object", "properties": {"tag": {"type": "string"}}}}`, mustJSON(t, doc))
+	})
+
+	t.Run("should pass other fragments of the root to the loader", func(t *testing.T) {
+		integrity := loading.Integrity(specFS["models/pet.json"].Data)
+
+		doc, err := Inline(ctx, "models/pet.json#integrity="+integrity, loading.WithFS(specFS))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"type": "object", "properties": {"tag": {"type": "string"}}}`, mustJSON(t, doc))
+
+		docs, err := Documents(ctx, "models/pet.json#integrity="+integrity, loading.WithFS(specFS))
+		require.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Contains(t, docs, "models/pet.json")
+
+		_, err = NewResolver(loading.WithFS(specFS)).Inline(ctx, "models/pet.json#integrity="+loading.Integrity(nil))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+		var integrityErr *loading.IntegrityError
+		require.ErrorAs(t, err, &integrityErr)
+	})
+
+	t.Run("should leave cycles across documents in place", func(t *testing.T) {
+		doc, err := Inline(ctx, "cycle/a.json", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{"b": {"a": {"$ref": "#"}}}`, mustJSON(t, doc))
+
+		doc, err = Inline(ctx, "cycle/root.json", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		assert.JSONEqT(t, `{"a": {"b": {"a": {"$ref": "cycle/a.json#"}}}}`, mustJSON(t, doc))
+	})
+
+	t.Run("should load every document once", func(t *testing.T) {
+		var loads atomic.Int32
+		resolver := NewResolver(loading.WithFS(specFS), loading.WithHooks(loading.Hooks{
+			OnStart: func(context.Context, loading.Event) { loads.Add(1) },
+		}))
+
+		_, err := resolver.Inline(ctx, "root.json")
+		require.NoError(t, err)
+		assert.EqualT(t, int32(3), loads.Load())
+
+		_, err = resolver.Documents(ctx, "models/pet.json")
+		require.NoError(t, err)
+		assert.EqualT(t, int32(3), loads.Load())
+	})
+
+	t.Run("should resolve references in remote documents", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/specs/root.json", func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte(`{"pet": {"$ref": "models/pet.json#/definitions/pet"}}`))
+		})
+		mux.HandleFunc("/specs/models/pet.json", func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte(`{"definitions": {"pet": {"name": {"$ref": "/common/string.json"}}}}`))
+		})
+		mux.HandleFunc("/common/string.json", func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte(`{"type": "string"}`))
+		})
+		serv := httptest.NewServer(mux)
+		t.Cleanup(serv.Close)
+
+		doc, err := Inline(ctx, serv.URL+"/specs/root.json")
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"pet": {"name": {"type": "string"}}}`, mustJSON(t, doc))
+
+		docs, err := Documents(ctx, serv.URL+"/specs/root.json")
+		require.NoError(t, err)
+		assert.Len(t, docs, 3)
+		assert.Contains(t, docs, serv.URL+"/specs/models/pet.json")
+		assert.Contains(t, docs, serv.URL+"/common/string.json")
+	})
+
+	t.Run("should fetch a remote archive once", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range map[string]string{
+			"specs/root.json":   `{"pet": {"$ref": "pet.json"}, "tag": {"$ref": "common.json#/tag"}}`,
+			"specs/pet.json":    `{"type": "object", "properties": {"tag": {"$ref": "common.json#/tag"}}}`,
+			"specs/common.json": `{"tag": {"type": "string"}}`,
+		} {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		var downloads atomic.Int32
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			downloads.Add(1)
+			_, _ = rw.Write(buf.Bytes())
+		}))
+		t.Cleanup(serv.Close)
+
+		doc, err := Inline(ctx, serv.URL+"/bundle.zip!/specs/root.json")
+		require.NoError(t, err)
+		assert.JSONEqT(t,
+			`{"pet": {"type": "object", "properties": {"tag": {"type": "string"}}}, "tag": {"type": "string"}}`,
+			mustJSON(t, doc),
+		)
+		assert.EqualT(t, int32(1), downloads.Load())
+	})
+
+	t.Run("should fail on an unresolved JSON pointer", func(t *testing.T) {
+		_, err := Inline(ctx, "broken.json", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+		assert.StringContainsT(t, err.Error(), "/nowhere")
+	})
+
+	t.Run("should fail on a missing document", func(t *testing.T) {
+		_, err := Inline(ctx, "dangling.json", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+		require.ErrorIs(t, err, loading.ErrLoader)
+	})
+
+	t.Run("should fail on a document that is not an object", func(t *testing.T) {
+		_, err := Inline(ctx, "array.json", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+
+		_, err = Inline(ctx, "root.json#/definitions/node/type", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+	})
+}
+
+func TestDocuments(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should collect all documents by canonical URI", func(t *testing.T) {
+		docs, err := Documents(ctx, "./root.json#/definitions", loading.WithFS(specFS))
+		require.NoError(t, err)
+
+		require.Len(t, docs, 3)
+		require.Contains(t, docs, "root.json")
+		require.Contains(t, docs, "common.yaml")
+		require.Contains(t, docs, "models/pet.json")
+
+		assert.JSONEqT(t, `{"info": {"title": "pet store"}, "tag": {"type": "string"}}`, mustJSON(t, docs["common.yaml"]))
+		assert.JSONEqT(t, `{"type": "object", "properties": {"tag": {"$ref": "../common.yaml#/tag"}}}`, mustJSON(t, docs["models/pet.json"]))
+	})
+
+	t.Run("should collect documents with cycles", func(t *testing.T) {
+		docs, err := Documents(ctx, "cycle/a.json", loading.WithFS(specFS))
+		require.NoError(t, err)
+		assert.Len(t, docs, 2)
+	})
+
+	t.Run("should check all references", func(t *testing.T) {
+		_, err := Documents(ctx, "broken.json", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+
+		_, err = Documents(ctx, "dangling.json", loading.WithFS(specFS))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrRef)
+	})
+}
+
+func TestResolveURI(t *testing.T) {
+	for _, tc := range []struct {
+		base     string
+		ref      string
+		expected string
+	}{
+		{base: "specs/root.yaml", ref: "pet.yaml", expected: "specs/pet.yaml"},
+		{base: "specs/root.yaml", ref: "../common.yaml#/info", expected: "common.yaml#/info"},
+		{base: "https://example.com/specs/root.yaml", ref: "pet.yaml", expected: "https://example.com/specs/pet.yaml"},
+		{base: `C:\specs\root.yaml`, ref: "pet.yaml", expected: "C:/specs/pet.yaml"},
+		{base: `C:\specs\root.yaml`, ref: "../models/pet.yaml#/definitions/pet", expected: "C:/models/pet.yaml#/definitions/pet"},
+		{base: "c:/specs/root.yaml", ref: "#/definitions/pet", expected: "c:/specs/root.yaml#/definitions/pet"},
+		{base: `C:\specs\root.yaml`, ref: `D:\other\pet.yaml`, expected: "D:/other/pet.yaml"},
+		{base: "specs/root.yaml", ref: "D:/other/pet.yaml", expected: "D:/other/pet.yaml"},
+	} {
+		t.Run("should resolve "+tc.ref+" against "+tc.base, func(t *testing.T) {
+			base, err := parseURI(tc.base)
+			require.NoError(t, err)
+
+			resolved, err := resolveURI(base, tc.ref)
+			require.NoError(t, err)
+
+			key := documentKey(resolved)
+			if resolved.Fragment != "" {
+				key += "#" + resolved.Fragment
+			}
+			assert.EqualT(t, tc.expected, key)
+		})
+	}
+
+	t.Run("should load documents from a root with a drive letter", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"C:/specs/root.json":       &fstest.MapFile{Data: []byte(`{"pet": {"$ref": "models/pet.json#/pet"}}`)},
+			"C:/specs/models/pet.json": &fstest.MapFile{Data: []byte(`{"pet": {"type": "object"}}`)},
+		}
+
+		docs, err := Documents(context.Background(), `C:\specs\root.json`, loading.WithFS(fsys))
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		require.Contains(t, docs, "C:/specs/root.json")
+		require.Contains(t, docs, "C:/specs/models/pet.json")
+
+		doc, err := Inline(context.Background(), `C:\specs\root.json`, loading.WithFS(fsys))
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"pet": {"type": "object"}}`, mustJSON(t, doc))
+	})
+}
+
+func mustJSON(t *testing.T, doc jsonutils.JSONMapSlice) string {
+	t.Helper()
+
+	b, err := jsonutils.WriteJSON(doc)
+	require.NoError(t, err)
+
+	return string(b)
+}
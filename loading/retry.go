@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultJitter         = 0.2
+)
+
+// RetryPolicy describes how failed remote loads are retried.
+//
+// Retries apply to transport errors (e.g. a connection reset) and to responses with a retryable status code.
+//
+// Backoff is exponential: the n-th retry waits for InitialBackoff * 2^(n-1), capped to MaxBackoff,
+// with some random jitter.
+//
+// Whenever the server responds with a "Retry-After" header, the delay it indicates is used instead.
+// No retry is attempted if this delay exceeds MaxBackoff: a server cannot stall the loader for longer than that.
+//
+// The timeout set by [WithTimeout] is a budget for all attempts: no retry is attempted if the
+// next delay would exceed this budget.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	//
+	// A value lower than 2 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. Defaults to 5s.
+	MaxBackoff time.Duration
+
+	// Jitter is the maximum fraction of the backoff delay that is randomly added or removed.
+	//
+	// The value is between 0 and 1. Defaults to 0.2. A negative value disables jitter.
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP status codes that trigger a retry.
+	//
+	// Defaults to 429, 502, 503 and 504.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried by default.
+func DefaultRetryableStatusCodes() []int {
+	return []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+
+	switch {
+	case p.Jitter == 0:
+		p.Jitter = defaultJitter
+	case p.Jitter < 0:
+		p.Jitter = 0
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = DefaultRetryableStatusCodes()
+	}
+
+	return p
+}
+
+// next determines if the failed attempt may be retried, and how long to wait before that.
+func (p RetryPolicy) next(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var wait time.Duration
+
+	var loadErr *LoadError
+	switch {
+	case errors.As(err, &loadErr) && loadErr.StatusCode != 0:
+		if !slices.Contains(p.RetryableStatusCodes, loadErr.StatusCode) {
+			return 0, false
+		}
+
+		if retryAfter, ok := parseRetryAfter(loadErr.Header.Get("Retry-After"), time.Now()); ok {
+			if retryAfter > p.MaxBackoff {
+				return 0, false
+			}
+
+			wait = retryAfter
+		} else {
+			wait = p.backoff(attempt)
+		}
+	case isTransportError(err):
+		wait = p.backoff(attempt)
+	default:
+		return 0, false
+	}
+
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < wait {
+		// no need to wait: the budget would be exhausted before the next attempt
+		return 0, false
+	}
+
+	return wait, true
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MaxBackoff
+	if shift := attempt - 1; shift < 32 { //nolint:mnd // prevents the shift from overflowing
+		wait = min(p.InitialBackoff<<shift, p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delta := p.Jitter * float64(wait) * (2*rand.Float64() - 1) //nolint:gosec,mnd // no need for a cryptographic random source to compute jitter
+		wait += time.Duration(delta)
+	}
+
+	return max(wait, 0)
+}
+
+// parseRetryAfter parses the value of a "Retry-After" header, expressed either
+// as a number of seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+// transportError wraps an error that occurred while exchanging with a remote server.
+type transportError struct {
+	error
+}
+
+func (e transportError) Unwrap() error {
+	return e.error
+}
+
+func isTransportError(err error) bool {
+	var transportErr transportError
+
+	return errors.As(err, &transportErr) &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	fastRetry := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+
+	t.Run("should retry on retryable status codes", func(t *testing.T) {
+		for _, status := range DefaultRetryableStatusCodes() {
+			t.Run(http.StatusText(status), func(t *testing.T) {
+				ts, attempts := serveFailuresThenOK(status, 2)
+				defer ts.Close()
+
+				b, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry))
+				require.NoError(t, err)
+				assert.EqualT(t, "the content", string(b))
+				assert.EqualT(t, int32(3), attempts.Load())
+			})
+		}
+	})
+
+	t.Run("should give up after max attempts", func(t *testing.T) {
+		ts, attempts := serveFailuresThenOK(http.StatusServiceUnavailable, 5)
+		defer ts.Close()
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.EqualT(t, int32(3), attempts.Load())
+	})
+
+	t.Run("should not retry without a retry policy", func(t *testing.T) {
+		ts, attempts := serveFailuresThenOK(http.StatusServiceUnavailable, 1)
+		defer ts.Close()
+
+		_, err := LoadFromFileOrHTTP(ts.URL)
+		require.Error(t, err)
+		assert.EqualT(t, int32(1), attempts.Load())
+	})
+
+	t.Run("should not retry on a non-retryable status code", func(t *testing.T) {
+		ts, attempts := serveFailuresThenOK(http.StatusNotFound, 1)
+		defer ts.Close()
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry))
+		require.Error(t, err)
+		assert.EqualT(t, int32(1), attempts.Load())
+	})
+
+	t.Run("should retry on custom status codes", func(t *testing.T) {
+		ts, attempts := serveFailuresThenOK(http.StatusInternalServerError, 1)
+		defer ts.Close()
+
+		policy := fastRetry
+		policy.RetryableStatusCodes = []int{http.StatusInternalServerError}
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(policy))
+		require.NoError(t, err)
+		assert.EqualT(t, int32(2), attempts.Load())
+	})
+
+	t.Run("should retry on transport errors", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := &http.Client{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				if attempts.Add(1) < 2 {
+					return nil, errors.New("connection reset")
+				}
+
+				return http.DefaultTransport.RoundTrip(r)
+			}),
+		}
+		ts := httptest.NewServer(http.HandlerFunc(serveOK))
+		defer ts.Close()
+
+		b, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry), WithHTTPClient(client))
+		require.NoError(t, err)
+		assert.EqualT(t, "the content", string(b))
+		assert.EqualT(t, int32(2), attempts.Load())
+	})
+
+	t.Run("should honor Retry-After", func(t *testing.T) {
+		var attempts atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 2 {
+				rw.Header().Set("Retry-After", "0")
+				rw.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			serveOK(rw, r)
+		}))
+		defer ts.Close()
+
+		policy := fastRetry
+		policy.InitialBackoff = time.Hour // Retry-After takes precedence
+		policy.MaxBackoff = time.Hour
+
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(policy))
+		require.NoError(t, err)
+		assert.EqualT(t, int32(2), attempts.Load())
+	})
+
+	t.Run("should not retry when Retry-After exceeds the max backoff", func(t *testing.T) {
+		var attempts atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			attempts.Add(1)
+			rw.Header().Set("Retry-After", "86400")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		start := time.Now()
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry), WithTimeout(0))
+		require.Error(t, err)
+		assert.EqualT(t, int32(1), attempts.Load())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should not retry beyond the timeout budget", func(t *testing.T) {
+		var attempts atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			attempts.Add(1)
+			rw.Header().Set("Retry-After", "3600")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		start := time.Now()
+		_, err := LoadFromFileOrHTTP(ts.URL, WithRetry(fastRetry), WithTimeout(time.Second))
+		require.Error(t, err)
+		assert.EqualT(t, int32(1), attempts.Load())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should stop retrying when the context is cancelled", func(t *testing.T) {
+		ts, _ := serveFailuresThenOK(http.StatusServiceUnavailable, 5)
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		policy := fastRetry
+		policy.MaxAttempts = 100
+		policy.InitialBackoff = 10 * time.Millisecond
+		policy.MaxBackoff = 10 * time.Millisecond
+
+		_, err := LoadFromFileOrHTTPContext(ctx, ts.URL, WithRetry(policy), WithTimeout(0))
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("should apply defaults", func(t *testing.T) {
+		p := RetryPolicy{MaxAttempts: 2}.withDefaults()
+		assert.EqualT(t, defaultInitialBackoff, p.InitialBackoff)
+		assert.EqualT(t, defaultMaxBackoff, p.MaxBackoff)
+		assert.EqualT(t, defaultJitter, p.Jitter)
+		assert.Equal(t, DefaultRetryableStatusCodes(), p.RetryableStatusCodes)
+	})
+
+	t.Run("should back off exponentially up to the max", func(t *testing.T) {
+		p := RetryPolicy{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     50 * time.Millisecond,
+			Jitter:         -1,
+		}.withDefaults()
+
+		assert.EqualT(t, 10*time.Millisecond, p.backoff(1))
+		assert.EqualT(t, 20*time.Millisecond, p.backoff(2))
+		assert.EqualT(t, 40*time.Millisecond, p.backoff(3))
+		assert.EqualT(t, 50*time.Millisecond, p.backoff(4))
+		assert.EqualT(t, 50*time.Millisecond, p.backoff(100))
+	})
+
+	t.Run("should give up when Retry-After exceeds the max backoff", func(t *testing.T) {
+		p := RetryPolicy{
+			MaxAttempts: 2,
+			MaxBackoff:  50 * time.Millisecond,
+		}.withDefaults()
+		err := &LoadError{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"86400"}},
+		}
+
+		_, ok := p.next(context.Background(), 1, err)
+		assert.FalseT(t, ok)
+
+		err.Header.Set("Retry-After", "0")
+		wait, ok := p.next(context.Background(), 1, err)
+		require.TrueT(t, ok)
+		assert.EqualT(t, time.Duration(0), wait)
+	})
+
+	t.Run("should add jitter to the backoff", func(t *testing.T) {
+		p := RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			Jitter:         0.5,
+		}.withDefaults()
+
+		for range 100 {
+			wait := p.backoff(1)
+			assert.GreaterOrEqual(t, wait, 50*time.Millisecond)
+			assert.LessOrEqual(t, wait, 150*time.Millisecond)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		Value    string
+		Expected time.Duration
+		OK       bool
+	}{
+		{Value: "120", Expected: 2 * time.Minute, OK: true},
+		{Value: " 0 ", Expected: 0, OK: true},
+		{Value: "Wed, 01 Oct 2025 12:00:30 GMT", Expected: 30 * time.Second, OK: true},
+		{Value: "Wed, 01 Oct 2025 11:00:00 GMT", Expected: 0, OK: true},
+		{Value: ""},
+		{Value: "-1"},
+		{Value: "soon"},
+	} {
+		t.Run(tc.Value, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tc.Value, now)
+			assert.EqualT(t, tc.OK, ok)
+			assert.EqualT(t, tc.Expected, wait)
+		})
+	}
+}
+
+// serveFailuresThenOK serves a failure status code for the given number of times, then serves some content.
+func serveFailuresThenOK(status int, failures int32) (*httptest.Server, *atomic.Int32) {
+	var attempts atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			rw.WriteHeader(status)
+
+			return
+		}
+
+		serveOK(rw, r)
+	}))
+
+	return ts, &attempts
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// URLRewrite maps the URLs starting with a prefix to another location,
+// e.g. a local mirror of some remote documents.
+//
+// Exactly one of Dir, FS or BaseURL must be set.
+//
+// The remainder of the URL after the prefix is appended to the new location. For example, with the prefix
+// "https://schemas.example.com/", the URL "https://schemas.example.com/v1/pet.yaml" is rewritten as "{Dir}/v1/pet.yaml".
+type URLRewrite struct {
+	// Prefix is the beginning of the rewritten URLs, e.g. "https://schemas.example.com/".
+	Prefix string
+
+	// Dir is a local directory.
+	Dir string
+
+	// FS is a file system, e.g. an [embed.FS].
+	FS fs.FS
+
+	// BaseURL is another base URL, e.g. "https://mirror.example.com/schemas/".
+	BaseURL string
+}
+
+// rewriteURL applies the [URLRewrite] with the longest matching prefix to a path.
+//
+// It returns the rewritten path, and the options to load it with: rewrites do not apply again.
+func (o options) rewriteURL(pth string) (string, options, error) {
+	if len(o.rewrites) == 0 && !o.strictRewrites {
+		return pth, o, nil
+	}
+
+	rewritten := o
+	rewritten.rewrites = nil
+	rewritten.strictRewrites = false
+
+	var (
+		rule  URLRewrite
+		found bool
+	)
+
+	for _, candidate := range o.rewrites {
+		if strings.HasPrefix(pth, candidate.Prefix) && (!found || len(candidate.Prefix) > len(rule.Prefix)) {
+			rule = candidate
+			found = true
+		}
+	}
+
+	if !found {
+		if o.strictRewrites && isRemoteURL(pth) {
+			return "", o, fmt.Errorf("no URL rewrite matches %q, and strict rewrites are enabled: %w", pth, ErrLoader)
+		}
+
+		return pth, rewritten, nil
+	}
+
+	rest := strings.TrimPrefix(pth, rule.Prefix)
+
+	switch {
+	case countTargets(rule) != 1:
+		return "", o, fmt.Errorf("invalid URL rewrite for prefix %q: exactly one of Dir, FS or BaseURL must be set: %w", rule.Prefix, ErrLoader)
+
+	case rule.BaseURL != "":
+		return rule.BaseURL + rest, rewritten, nil
+
+	default:
+		rel := path.Clean(strings.TrimLeft(rest, "/"))
+		if !fs.ValidPath(rel) || rel == "." {
+			return "", o, fmt.Errorf("invalid path %q after rewriting %q: %w", rel, pth, ErrLoader)
+		}
+
+		if rule.FS != nil {
+			WithFS(rule.FS)(&rewritten)
+			rewritten.rewrittenFS = true
+
+			return rel, rewritten, nil
+		}
+
+		return filepath.Join(rule.Dir, filepath.FromSlash(rel)), rewritten, nil
+	}
+}
+
+func countTargets(rule URLRewrite) int {
+	var count int
+
+	for _, isSet := range []bool{rule.Dir != "", rule.FS != nil, rule.BaseURL != ""} {
+		if isSet {
+			count++
+		}
+	}
+
+	return count
+}
+
+// isRemoteURL tells if a path is a remote URL, including a path to an entry in a remote archive.
+func isRemoteURL(pth string) bool {
+	scheme, _ := uriScheme(pth)
+
+	return scheme == schemeHTTP || scheme == schemeHTTPS
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestURLRewrites(t *testing.T) {
+	const prefix = "https://schemas.example.com/"
+
+	serv := httptest.NewServer(http.HandlerFunc(serveYAMLPetStore))
+	t.Cleanup(serv.Close)
+
+	mirror := fstest.MapFS{
+		"v1/pet.json": &fstest.MapFile{Data: jsonPetStore},
+		"bundle.zip":  &fstest.MapFile{Data: mustZip(t, map[string][]byte{"pet.yaml": yamlPetStore})},
+	}
+
+	t.Run("should rewrite a URL to a local directory", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(prefix+"petstore_fixture.yaml", WithURLRewrites(URLRewrite{Prefix: prefix, Dir: "fixtures"}))
+		require.NoError(t, err)
+		assert.Equal(t, yamlPetStore, b)
+	})
+
+	t.Run("should rewrite a URL to a file system", func(t *testing.T) {
+		doc, err := JSONDoc(prefix+"v1/pet.json", WithURLRewrites(URLRewrite{Prefix: prefix, FS: mirror}))
+		require.NoError(t, err)
+		assert.JSONEqBytes(t, jsonPetStore, doc)
+	})
+
+	t.Run("should rewrite a URL to another base URL", func(t *testing.T) {
+		doc, err := YAMLDoc(prefix+"v1/pet.yaml", WithURLRewrites(URLRewrite{Prefix: prefix, BaseURL: serv.URL + "/mirror/"}))
+		require.NoError(t, err)
+		assert.JSONEqBytes(t, jsonPetStore, doc)
+	})
+
+	t.Run("should rewrite the URL of a remote archive", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(prefix+"bundle.zip!/pet.yaml",
+			WithURLRewrites(URLRewrite{Prefix: prefix, FS: mirror}),
+			WithStrictURLRewrites(true),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, yamlPetStore, b)
+	})
+
+	t.Run("should stream a rewritten document", func(t *testing.T) {
+		rdr, meta, err := Open(prefix+"v1/pet.json", WithURLRewrites(URLRewrite{Prefix: prefix, FS: mirror}))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = rdr.Close()
+		})
+
+		b, err := io.ReadAll(rdr)
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+		assert.EqualT(t, "v1/pet.json", meta.URL)
+	})
+
+	t.Run("should apply the longest matching prefix", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(prefix+"v1/pet.json", WithURLRewrites(
+			URLRewrite{Prefix: prefix, Dir: "missing"},
+			URLRewrite{Prefix: prefix + "v1/", FS: fstest.MapFS{"pet.json": &fstest.MapFile{Data: jsonPetStore}}},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, jsonPetStore, b)
+	})
+
+	t.Run("should not rewrite other URLs", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(serv.URL+"/pet.yaml", WithURLRewrites(URLRewrite{Prefix: prefix, FS: mirror}))
+		require.NoError(t, err)
+		assert.Equal(t, yamlPetStore, b)
+	})
+
+	t.Run("with strict rewrites", func(t *testing.T) {
+		opts := []Option{WithURLRewrites(URLRewrite{Prefix: prefix, FS: mirror}), WithStrictURLRewrites(true)}
+
+		t.Run("should fail on a remote URL that is not rewritten", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/pet.yaml", opts...)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+			assert.StringContainsT(t, err.Error(), "no URL rewrite")
+
+			_, _, err = Open(serv.URL+"/pet.yaml", opts...)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should fail on a remote archive that is not rewritten", func(t *testing.T) {
+			_, err := LoadFromFileOrHTTP(serv.URL+"/bundle.zip!/pet.yaml", opts...)
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+
+		t.Run("should load local documents", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP("fixtures/petstore_fixture.json", opts...)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+
+		t.Run("should load a rewritten URL", func(t *testing.T) {
+			b, err := LoadFromFileOrHTTP(prefix+"v1/pet.json", opts...)
+			require.NoError(t, err)
+			assert.Equal(t, jsonPetStore, b)
+		})
+	})
+
+	t.Run("should fail on an invalid rewrite", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(prefix+"v1/pet.json", WithURLRewrites(URLRewrite{Prefix: prefix, Dir: "fixtures", FS: mirror}))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.StringContainsT(t, err.Error(), "exactly one of")
+	})
+
+	t.Run("should not rewrite outside of the target directory", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(prefix+"../../etc/passwd", WithURLRewrites(URLRewrite{Prefix: prefix, Dir: "fixtures"}))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.StringContainsT(t, err.Error(), "invalid path")
+	})
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+	schemeFile  = "file"
+	schemeData  = "data"
+)
+
+// SchemeLoader loads a document from a URI with a given scheme.
+//
+// The loader receives the full URI, including its scheme.
+type SchemeLoader func(ctx context.Context, uri string) ([]byte, error)
+
+// uriScheme returns the lower-cased scheme of an URI, as defined by RFC 3986:
+//
+//	scheme = ALPHA *( ALPHA / DIGIT / "+" / "-" / "." ) ":"
+//
+// Single-letter schemes are not reported, since these are most likely windows drive letters.
+func uriScheme(pth string) (string, bool) {
+	scheme, _, found := strings.Cut(pth, ":")
+	if !found || len(scheme) < 2 { //nolint:mnd // a single letter is a drive letter, not a scheme
+		return "", false
+	}
+
+	for i, c := range scheme {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return "", false
+		}
+	}
+
+	return strings.ToLower(scheme), true
+}
+
+// loadDataURI decodes the content of a data URI, as defined by RFC 2397:
+//
+//	dataurl    := "data:" [ mediatype ] [ ";base64" ] "," data
+//
+// The media type is ignored.
+func loadDataURI(uri string) ([]byte, error) {
+	_, rest, _ := strings.Cut(uri, ":")
+	header, data, found := strings.Cut(rest, ",")
+	if !found {
+		return nil, fmt.Errorf("invalid data URI: missing ',' separator: %w", ErrLoader)
+	}
+
+	if !strings.HasSuffix(strings.ToLower(header), ";base64") {
+		decoded, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data URI: %w: %w", err, ErrLoader)
+		}
+
+		return []byte(decoded), nil
+	}
+
+	unescaped, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data URI: %w: %w", err, ErrLoader)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(unescaped)
+	if err != nil {
+		// tolerate unpadded base64
+		var rawErr error
+		decoded, rawErr = base64.RawStdEncoding.DecodeString(unescaped)
+		if rawErr != nil {
+			return nil, fmt.Errorf("invalid base64 data URI: %w: %w", err, ErrLoader)
+		}
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestURIScheme(t *testing.T) {
+	for _, tc := range []struct {
+		Path     string
+		Expected string
+		IsScheme bool
+	}{
+		{Path: "http://example.com/spec.json", Expected: "http", IsScheme: true},
+		{Path: "HTTPS://example.com/spec.json", Expected: "https", IsScheme: true},
+		{Path: "data:,abc", Expected: "data", IsScheme: true},
+		{Path: "mem://doc", Expected: "mem", IsScheme: true},
+		{Path: "x-my.corp+v1://doc", Expected: "x-my.corp+v1", IsScheme: true},
+		{Path: "file:///a/b", Expected: "file", IsScheme: true},
+		{Path: "/a/b/c.yaml"},
+		{Path: "./a/b/c.yaml"},
+		{Path: `C:\a\b\c.yaml`},
+		{Path: "C:/a/b/c.yaml"},
+		{Path: "1abc://doc"},
+		{Path: "my folder:doc"},
+		{Path: ""},
+	} {
+		t.Run(tc.Path, func(t *testing.T) {
+			scheme, ok := uriScheme(tc.Path)
+			assert.EqualT(t, tc.IsScheme, ok)
+			assert.EqualT(t, tc.Expected, scheme)
+		})
+	}
+}
+
+func TestLoadDataURI(t *testing.T) {
+	t.Run("should decode a plain data URI", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(`data:application/json,{"a":%20"b"}`)
+		require.NoError(t, err)
+		assert.EqualT(t, `{"a": "b"}`, string(b))
+	})
+
+	t.Run("should decode a data URI without media type", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(`data:,content`)
+		require.NoError(t, err)
+		assert.EqualT(t, "content", string(b))
+	})
+
+	t.Run("should decode a base64 data URI", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(`data:application/yaml;charset=utf-8;base64,YTogYgo=`)
+		require.NoError(t, err)
+		assert.EqualT(t, "a: b\n", string(b))
+	})
+
+	t.Run("should decode an unpadded base64 data URI", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP(`data:;base64,YTogYgo`)
+		require.NoError(t, err)
+		assert.EqualT(t, "a: b\n", string(b))
+	})
+
+	t.Run("should load a YAML document from a data URI", func(t *testing.T) {
+		doc, err := YAMLDoc(`data:application/yaml,a:%20b`)
+		require.NoError(t, err)
+		assert.JSONEqT(t, `{"a":"b"}`, string(doc))
+	})
+
+	t.Run("should fail on a data URI without separator", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(`data:application/json`)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should fail on an invalid base64 data URI", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(`data:;base64,!!!`)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+
+	t.Run("should fail on an invalid escaped data URI", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP(`data:,%GG`)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+	})
+}
+
+func TestWithSchemeLoader(t *testing.T) {
+	docs := map[string]string{
+		"mem://pet":  "pet content",
+		"mem://user": "user content",
+	}
+	memLoader := func(_ context.Context, uri string) ([]byte, error) {
+		doc, ok := docs[uri]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+
+		return []byte(doc), nil
+	}
+
+	t.Run("should load from a registered scheme", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP("mem://pet", WithSchemeLoader("mem", memLoader))
+		require.NoError(t, err)
+		assert.EqualT(t, "pet content", string(b))
+	})
+
+	t.Run("should match a registered scheme regardless of case", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP("MEM://user", WithSchemeLoader("Mem:", func(ctx context.Context, uri string) ([]byte, error) {
+			return memLoader(ctx, strings.ToLower(uri))
+		}))
+		require.NoError(t, err)
+		assert.EqualT(t, "user content", string(b))
+	})
+
+	t.Run("should propagate the context to a registered loader", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+		b, err := LoadFromFileOrHTTPContext(ctx, "ctx://", WithSchemeLoader("ctx", func(ctx context.Context, _ string) ([]byte, error) {
+			v, _ := ctx.Value(ctxKey{}).(string)
+
+			return []byte(v), nil
+		}))
+		require.NoError(t, err)
+		assert.EqualT(t, "value", string(b))
+	})
+
+	t.Run("should override a built-in scheme", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(serveOK))
+		defer ts.Close()
+
+		b, err := LoadFromFileOrHTTP(ts.URL, WithSchemeLoader("http", func(_ context.Context, _ string) ([]byte, error) {
+			return []byte("overridden"), nil
+		}))
+		require.NoError(t, err)
+		assert.EqualT(t, "overridden", string(b))
+	})
+
+	t.Run("should fail on an unknown scheme", func(t *testing.T) {
+		_, err := LoadFromFileOrHTTP("unknown://pet", WithSchemeLoader("mem", memLoader))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrLoader)
+		assert.StringContainsT(t, err.Error(), `unsupported URI scheme "unknown"`)
+	})
+
+	t.Run("should still load local files with the file scheme", func(t *testing.T) {
+		b, err := LoadFromFileOrHTTP("file://fixtures/petstore_fixture.yaml",
+			WithFS(embeddedFixtures),
+			WithSchemeLoader("mem", memLoader),
+		)
+		require.NoError(t, err)
+		assert.YAMLEqT(t, string(yamlPetStore), string(b))
+	})
+
+	t.Run("with LoadStrategy", func(t *testing.T) {
+		local := func(string) ([]byte, error) { return []byte("local"), nil }
+		remote := func(string) ([]byte, error) { return []byte("remote"), nil }
+
+		t.Run("should serve a registered scheme", func(t *testing.T) {
+			b, err := LoadStrategy("mem://pet", local, remote, WithSchemeLoader("mem", memLoader))("mem://pet")
+			require.NoError(t, err)
+			assert.EqualT(t, "pet content", string(b))
+		})
+
+		t.Run("should serve a data URI", func(t *testing.T) {
+			b, err := LoadStrategy("data:,inline", local, remote)("data:,inline")
+			require.NoError(t, err)
+			assert.EqualT(t, "inline", string(b))
+		})
+
+		t.Run("should serve local strategy for a windows drive letter", func(t *testing.T) {
+			b, err := LoadStrategy(`C:\folder\file.yaml`, local, remote)(`C:\folder\file.yaml`)
+			require.NoError(t, err)
+			assert.EqualT(t, "local", string(b))
+		})
+
+		t.Run("should not serve an unknown scheme", func(t *testing.T) {
+			_, err := LoadStrategy("ftp://host/file.yaml", local, remote)("ftp://host/file.yaml")
+			require.Error(t, err)
+			require.ErrorIs(t, err, ErrLoader)
+		})
+	})
+}
@@ -6,10 +6,12 @@ package loading
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path"
 	"testing"
+	"testing/fstest"
 )
 
 // embedded test files
@@ -84,6 +86,52 @@ func serveRequireHeaderFunc(key, value string) func(http.ResponseWriter, *http.R
 	}
 }
 
+func serveBearerTokenFunc(token string) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			rw.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		serveOK(rw, r)
+	}
+}
+
+// slowFS is a file system that reads files one byte at a time, calling onRead at every read.
+type slowFS struct {
+	fstest.MapFS
+
+	onRead func()
+}
+
+func (s slowFS) Open(name string) (fs.File, error) {
+	f, err := s.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return slowFile{File: f, onRead: s.onRead}, nil
+}
+
+type slowFile struct {
+	fs.File
+
+	onRead func()
+}
+
+func (f slowFile) Read(p []byte) (int, error) {
+	if f.onRead != nil {
+		f.onRead()
+	}
+
+	if len(p) > 1 {
+		p = p[:1]
+	}
+
+	return f.File.Read(p)
+}
+
 func mustLoadFixture(name string) []byte {
 	const msg = "wrong embedded FS configuration: %w"
 	data, err := embeddedFixtures.ReadFile(path.Join("fixtures", name))
@@ -93,3 +141,9 @@ func mustLoadFixture(name string) []byte {
 
 	return data
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultDebounce     = 100 * time.Millisecond
+)
+
+// Version is a version of a document, emitted by [Watch].
+type Version struct {
+	// Data is the content of the document.
+	Data []byte
+
+	// Err is the error that occurred when checking or loading the document, in which case Data is nil.
+	Err error
+}
+
+// Watch watches a document, from either a file or a remote url, and emits a new [Version]
+// every time its content changes.
+//
+// The current version is emitted first. The document is then checked at the interval set by [WithPollInterval]:
+//   - a local file is checked for changes of its modification time and size
+//   - a document in a file system set with [WithFS], or any other kind of document, is loaded again and hashed
+//   - a remote document is fetched with a conditional request, using the "ETag" and "Last-Modified" of the previous response
+//
+// A new version is emitted once the document has remained unchanged for the delay set by [WithDebounce],
+// and only if its content differs from the previous version.
+//
+// Every version is verified against the integrity set with [WithIntegrity] or an "#integrity=" fragment,
+// and against the lockfile set with [WithLockfile]: a version that fails verification is emitted as an error.
+//
+// Errors are emitted as a [Version] with Err set, once until the document can be loaded again.
+//
+// The returned channel is closed when the context is done.
+func Watch(ctx context.Context, path string, opts ...Option) <-chan Version {
+	o := optionsWithDefaults(opts)
+	path, expected := o.splitIntegrity(path)
+	versions := make(chan Version)
+
+	w := &watcher{
+		options:  o,
+		versions: versions,
+		probe:    o.watchProbe(path),
+		load: func(ctx context.Context) ([]byte, error) {
+			return strategyLoader{options: o}.load(ctx, path)
+		},
+		verify: func(data []byte) error {
+			return o.verifyIntegrity(path, data, expected)
+		},
+	}
+
+	go func() {
+		defer close(versions)
+
+		w.run(ctx)
+	}()
+
+	return versions
+}
+
+// probeFunc checks a document. It returns the state of the document, which changes with the document,
+// and its content if it was loaded in the process.
+type probeFunc func(ctx context.Context) (state string, data []byte, err error)
+
+// watcher polls a document and emits its versions.
+type watcher struct {
+	options  options
+	versions chan<- Version
+	probe    probeFunc
+	load     func(ctx context.Context) ([]byte, error)
+	verify   func(data []byte) error // checks the integrity of every version
+
+	state   string // the last state of the document
+	digest  string // the digest of the last version emitted
+	lastErr string // the last error emitted
+}
+
+func (w *watcher) run(ctx context.Context) {
+	state, data, err := w.probe(ctx)
+	w.state = state
+	if !w.emit(ctx, data, err) {
+		return
+	}
+
+	var (
+		pending     bool
+		pendingData []byte
+	)
+
+	timer := time.NewTimer(w.options.pollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		state, data, err = w.probe(ctx)
+		switch {
+		case err != nil:
+			// the next successful check is a change
+			w.state = ""
+			pending = false
+
+			if !w.emit(ctx, nil, err) {
+				return
+			}
+		case state != w.state:
+			// wait for the document to settle
+			w.state = state
+			pending = true
+			pendingData = data
+		case pending:
+			pending = false
+			if data == nil {
+				data = pendingData
+			}
+			pendingData = nil
+
+			if !w.emit(ctx, data, nil) {
+				return
+			}
+		}
+
+		if pending {
+			timer.Reset(w.options.debounce)
+		} else {
+			timer.Reset(w.options.pollInterval)
+		}
+	}
+}
+
+// emit sends a new version of the document, unless its content is unchanged, or the same error was already sent.
+//
+// If data is nil, the document is loaded. It returns false when the context is done.
+func (w *watcher) emit(ctx context.Context, data []byte, err error) bool {
+	if err == nil && data == nil {
+		data, err = w.load(ctx)
+	}
+
+	if err == nil {
+		if err = w.verify(data); err != nil {
+			data = nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var version Version
+	if err != nil {
+		if err.Error() == w.lastErr {
+			return true
+		}
+
+		w.lastErr = err.Error()
+		w.digest = ""
+		version.Err = err
+	} else {
+		digest := Integrity(data)
+		if digest == w.digest {
+			return true
+		}
+
+		w.digest = digest
+		w.lastErr = ""
+		version.Data = data
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case w.versions <- version:
+		return true
+	}
+}
+
+// watchProbe determines how to check a document for changes.
+func (o options) watchProbe(path string) probeFunc {
+	target, rewritten, err := o.rewriteURL(path)
+	if err != nil {
+		return func(context.Context) (string, []byte, error) {
+			return "", nil, err
+		}
+	}
+
+	switch kind, _ := rewritten.strategyFor(target); {
+	case kind == strategyRemote:
+		return rewritten.httpProbe(target)
+	case kind == strategyLocal && rewritten.fs == nil:
+		return rewritten.statProbe(target)
+	default:
+		return func(ctx context.Context) (string, []byte, error) {
+			data, err := strategyLoader{options: rewritten}.load(ctx, target)
+			if err != nil {
+				return "", nil, err
+			}
+
+			return Integrity(data), data, nil
+		}
+	}
+}
+
+// statProbe checks a local file for changes of its modification time and size.
+func (o options) statProbe(pth string) probeFunc {
+	return func(context.Context) (string, []byte, error) {
+		name, err := o.localPath(pth)
+		if err != nil {
+			return "", nil, err
+		}
+
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", nil, localLoadError(pth, name, err)
+		}
+
+		return strconv.FormatInt(info.ModTime().UnixNano(), 10) + ":" + strconv.FormatInt(info.Size(), 10), nil, nil
+	}
+}
+
+// httpProbe checks a remote document with conditional requests.
+func (o options) httpProbe(pth string) probeFunc {
+	var (
+		cached *CacheEntry
+		state  string
+	)
+
+	return func(ctx context.Context) (string, []byte, error) {
+		if o.httpTimeout > 0 {
+			var cancel func()
+			ctx, cancel = context.WithTimeout(ctx, o.httpTimeout)
+			defer cancel()
+		}
+
+		result, err := fetchHTTPWithRetry(ctx, pth, o, cached)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if result.notModified {
+			return state, nil, nil
+		}
+
+		cached = &CacheEntry{
+			ETag:         result.header.Get("ETag"),
+			LastModified: result.header.Get("Last-Modified"),
+		}
+		state = Integrity(result.data)
+
+		return state, result.data, nil
+	}
+}
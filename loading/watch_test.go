@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright 2015-2025 go-swagger maintainers
+// SPDX-License-Identifier: Apache-2.0
+
+package loading
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const (
+	testPollInterval = 5 * time.Millisecond
+	testDebounce     = 10 * time.Millisecond
+	testQuiet        = 100 * time.Millisecond
+)
+
+func TestWatch(t *testing.T) {
+	t.Run("should watch a local file", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		name := filepath.Join(t.TempDir(), "spec.yaml")
+		mtime := time.Now().Add(-time.Hour)
+		writeFileAt(t, name, "version: 1", mtime)
+
+		versions := Watch(ctx, name, WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+		assert.EqualT(t, "version: 1", string(nextVersion(t, versions).Data))
+
+		writeFileAt(t, name, "version: 2", mtime.Add(time.Second))
+		assert.EqualT(t, "version: 2", string(nextVersion(t, versions).Data))
+
+		t.Run("should not emit a version with unchanged content", func(t *testing.T) {
+			writeFileAt(t, name, "version: 2", mtime.Add(2*time.Second))
+			noVersion(t, versions)
+		})
+
+		t.Run("should emit an error once", func(t *testing.T) {
+			require.NoError(t, os.Remove(name))
+
+			version := nextVersion(t, versions)
+			require.Error(t, version.Err)
+			require.ErrorIs(t, version.Err, ErrLoader)
+			assert.Nil(t, version.Data)
+			noVersion(t, versions)
+
+			writeFileAt(t, name, "version: 2", mtime)
+			assert.EqualT(t, "version: 2", string(nextVersion(t, versions).Data))
+		})
+
+		t.Run("should close the channel when the context is done", func(t *testing.T) {
+			cancel()
+
+			select {
+			case _, isOpen := <-versions:
+				assert.FalseT(t, isOpen)
+			case <-time.After(time.Second):
+				t.Fatal("expected the channel to be closed")
+			}
+		})
+	})
+
+	t.Run("should watch a file in a file system", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		fsys := &mutableFS{files: fstest.MapFS{"spec.json": &fstest.MapFile{Data: []byte(`{"version":1}`)}}}
+
+		versions := Watch(ctx, "spec.json", WithFS(fsys), WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+		assert.EqualT(t, `{"version":1}`, string(nextVersion(t, versions).Data))
+		noVersion(t, versions)
+
+		fsys.write("spec.json", `{"version":2}`)
+		assert.EqualT(t, `{"version":2}`, string(nextVersion(t, versions).Data))
+	})
+
+	t.Run("should debounce changes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		fsys := &mutableFS{files: fstest.MapFS{"spec.json": &fstest.MapFile{Data: []byte(`{"version":1}`)}}}
+
+		versions := Watch(ctx, "spec.json", WithFS(fsys), WithPollInterval(testPollInterval), WithDebounce(testQuiet))
+		assert.EqualT(t, `{"version":1}`, string(nextVersion(t, versions).Data))
+
+		// changes more frequent than the debounce delay are not emitted
+		for _, content := range []string{`{"version":2}`, `{"version":3}`, `{"version":4}`} {
+			fsys.write("spec.json", content)
+			time.Sleep(testQuiet / 4)
+		}
+
+		assert.EqualT(t, `{"version":4}`, string(nextVersion(t, versions).Data))
+	})
+
+	t.Run("should watch a remote document with conditional requests", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		var (
+			mx            sync.Mutex
+			content       = `{"version":1}`
+			etag          = `"v1"`
+			notModified   atomic.Int32
+			unconditional atomic.Int32
+		)
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			mx.Lock()
+			defer mx.Unlock()
+
+			if r.Header.Get("If-None-Match") == etag {
+				notModified.Add(1)
+				rw.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			unconditional.Add(1)
+			rw.Header().Set("ETag", etag)
+			_, _ = rw.Write([]byte(content))
+		}))
+		t.Cleanup(serv.Close)
+
+		versions := Watch(ctx, serv.URL+"/spec.json", WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+		assert.EqualT(t, `{"version":1}`, string(nextVersion(t, versions).Data))
+		noVersion(t, versions)
+		assert.Positive(t, notModified.Load())
+		assert.EqualT(t, int32(1), unconditional.Load())
+
+		mx.Lock()
+		content, etag = `{"version":2}`, `"v2"`
+		mx.Unlock()
+
+		assert.EqualT(t, `{"version":2}`, string(nextVersion(t, versions).Data))
+	})
+
+	t.Run("should verify the integrity of every version", func(t *testing.T) {
+		const (
+			genuine  = `{"version":1}`
+			tampered = `{"version":2}`
+		)
+
+		t.Run("with a file system", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			fsys := &mutableFS{files: fstest.MapFS{"spec.json": &fstest.MapFile{Data: []byte(genuine)}}}
+
+			versions := Watch(ctx, "spec.json#integrity="+Integrity([]byte(genuine)),
+				WithFS(fsys), WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+			assert.EqualT(t, genuine, string(nextVersion(t, versions).Data))
+
+			fsys.write("spec.json", tampered)
+			version := nextVersion(t, versions)
+			require.Error(t, version.Err)
+			var integrityErr *IntegrityError
+			require.ErrorAs(t, version.Err, &integrityErr)
+			assert.Nil(t, version.Data)
+		})
+
+		t.Run("with a remote document", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte(tampered))
+			}))
+			t.Cleanup(serv.Close)
+
+			versions := Watch(ctx, serv.URL+"/spec.json",
+				WithIntegrity(Integrity([]byte(genuine))), WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+			version := nextVersion(t, versions)
+			require.Error(t, version.Err)
+			var integrityErr *IntegrityError
+			require.ErrorAs(t, version.Err, &integrityErr)
+			assert.Nil(t, version.Data)
+			noVersion(t, versions)
+		})
+
+		t.Run("with a lockfile", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			fsys := &mutableFS{files: fstest.MapFS{"spec.json": &fstest.MapFile{Data: []byte(genuine)}}}
+			lock := NewLockfile()
+
+			versions := Watch(ctx, "spec.json",
+				WithFS(fsys), WithLockfile(lock), WithPollInterval(testPollInterval), WithDebounce(testDebounce))
+			assert.EqualT(t, genuine, string(nextVersion(t, versions).Data))
+
+			fsys.write("spec.json", tampered)
+			version := nextVersion(t, versions)
+			require.Error(t, version.Err)
+			var integrityErr *IntegrityError
+			require.ErrorAs(t, version.Err, &integrityErr)
+		})
+	})
+
+	t.Run("should emit an error for an invalid path", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		versions := Watch(ctx, "fixtures/nowhere.yaml", WithPollInterval(testPollInterval))
+
+		version := nextVersion(t, versions)
+		require.Error(t, version.Err)
+		noVersion(t, versions)
+	})
+}
+
+func nextVersion(t *testing.T, versions <-chan Version) Version {
+	t.Helper()
+
+	select {
+	case version, isOpen := <-versions:
+		require.TrueT(t, isOpen)
+
+		return version
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a new version")
+
+		return Version{}
+	}
+}
+
+func noVersion(t *testing.T, versions <-chan Version) {
+	t.Helper()
+
+	select {
+	case version := <-versions:
+		t.Fatalf("expected no new version, got %q (%v)", version.Data, version.Err)
+	case <-time.After(testQuiet):
+	}
+}
+
+func writeFileAt(t *testing.T, name, content string, mtime time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(name, mtime, mtime))
+}
+
+// mutableFS is a file system which content may change while it is read.
+type mutableFS struct {
+	mx    sync.Mutex
+	files fstest.MapFS
+}
+
+func (m *mutableFS) Open(name string) (fs.File, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	return m.files.Open(name)
+}
+
+func (m *mutableFS) ReadFile(name string) ([]byte, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	return m.files.ReadFile(name)
+}
+
+func (m *mutableFS) write(name, content string) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.files[name] = &fstest.MapFile{Data: []byte(content)}
+}
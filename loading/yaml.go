@@ -4,21 +4,33 @@
 package loading
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"path/filepath"
 
 	"github.com/go-openapi/swag/yamlutils"
 )
 
 // YAMLMatcher matches yaml for a file loader.
+//
+// A compression extension such as ".gz" is ignored, e.g. "spec.yaml.gz" is matched.
 func YAMLMatcher(path string) bool {
-	ext := filepath.Ext(path)
+	ext := filepath.Ext(trimCompressionExt(path))
 	return ext == ".yaml" || ext == ".yml"
 }
 
 // YAMLDoc loads a yaml document from either http or a file and converts it to json.
 func YAMLDoc(path string, opts ...Option) (json.RawMessage, error) {
-	yamlDoc, err := YAMLData(path, opts...)
+	return YAMLDocContext(context.Background(), path, opts...)
+}
+
+// YAMLDocContext loads a yaml document from either http or a file and converts it to json, with a context.
+//
+// See [LoadFromFileOrHTTPContext].
+func YAMLDocContext(ctx context.Context, path string, opts ...Option) (json.RawMessage, error) {
+	yamlDoc, err := YAMLDataContext(ctx, path, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -28,10 +40,75 @@ func YAMLDoc(path string, opts ...Option) (json.RawMessage, error) {
 
 // YAMLData loads a yaml document from either http or a file.
 func YAMLData(path string, opts ...Option) (any, error) {
-	data, err := LoadFromFileOrHTTP(path, opts...)
+	return YAMLDataContext(context.Background(), path, opts...)
+}
+
+// YAMLDataContext loads a yaml document from either http or a file, with a context.
+//
+// See [LoadFromFileOrHTTPContext].
+func YAMLDataContext(ctx context.Context, path string, opts ...Option) (any, error) {
+	o := optionsWithDefaults(opts)
+
+	data, err := strategyLoader{options: o}.Load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = toUTF8(path, data); err != nil {
+		return nil, err
+	}
+
+	yamlDoc, err := yamlutils.BytesToYAMLDoc(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = o.interpolateYAML(path, yamlDoc); err != nil {
+		return nil, err
+	}
+
+	return yamlDoc, nil
+}
+
+// YAMLDocs loads a stream of yaml documents separated by "---" from either http or a file,
+// and converts each document to json.
+//
+// The documents are returned in order. Empty documents are skipped.
+func YAMLDocs(path string, opts ...Option) ([]json.RawMessage, error) {
+	return YAMLDocsContext(context.Background(), path, opts...)
+}
+
+// YAMLDocsContext loads a stream of yaml documents from either http or a file and converts each document
+// to json, with a context.
+//
+// See [YAMLDocs] and [LoadFromFileOrHTTPContext].
+func YAMLDocsContext(ctx context.Context, path string, opts ...Option) ([]json.RawMessage, error) {
+	o := optionsWithDefaults(opts)
+
+	data, err := strategyLoader{options: o}.Load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = toUTF8(path, data); err != nil {
+		return nil, err
+	}
+
+	yamlDocs, err := yamlutils.BytesToYAMLDocs(data)
 	if err != nil {
 		return nil, err
 	}
 
-	return yamlutils.BytesToYAMLDoc(data)
+	errs := make([]error, 0, len(yamlDocs))
+	for index, yamlDoc := range yamlDocs {
+		if err = o.interpolateYAML(path, yamlDoc); err != nil {
+			errs = append(errs, fmt.Errorf("in YAML document at index %d: %w", index, err))
+		}
+	}
+
+	if err = errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return yamlutils.YAMLDocsToJSON(yamlDocs)
 }
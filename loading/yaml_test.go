@@ -4,6 +4,7 @@
 package loading
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -42,3 +43,71 @@ func TestYAMLDoc(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestYAMLDocContext(t *testing.T) {
+	t.Run("should retrieve pet store API as YAML", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveYAMLPetStore))
+		defer serv.Close()
+
+		s, err := YAMLDocContext(context.Background(), serv.URL)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("should not retrieve any doc with a cancelled context", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(serveYAMLPetStore))
+		defer serv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := YAMLDataContext(ctx, serv.URL)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestYAMLDocs(t *testing.T) {
+	const stream = `kind: ConfigMap
+name: first
+---
+kind: Secret
+name: second
+`
+
+	t.Run("should retrieve all documents in a YAML stream", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte(stream))
+		}))
+		defer serv.Close()
+
+		docs, err := YAMLDocs(serv.URL)
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.JSONEqBytes(t, []byte(`{"kind":"ConfigMap","name":"first"}`), docs[0])
+		assert.JSONEqBytes(t, []byte(`{"kind":"Secret","name":"second"}`), docs[1])
+	})
+
+	t.Run("should retrieve a single YAML document", func(t *testing.T) {
+		docs, err := YAMLDocs("fixtures/petstore_fixture.yaml")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.YAMLEqBytes(t, yamlPetStore, docs[0])
+	})
+
+	t.Run("should not retrieve any doc", func(t *testing.T) {
+		_, err := YAMLDocs("fixtures/nowhere.yaml")
+		require.Error(t, err)
+
+		_, err = YAMLDocs("data:,- a%0A- b")
+		require.Error(t, err)
+	})
+
+	t.Run("should not retrieve any doc with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := YAMLDocsContext(ctx, "fixtures/petstore_fixture.yaml")
+		require.Error(t, err)
+	})
+}
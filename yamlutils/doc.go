@@ -5,6 +5,7 @@
 //
 //   - [BytesToYAMLDoc] to construct a [yaml.Node] document
 //   - [YAMLToJSON] to convert a [yaml.Node] document to JSON bytes
+//   - [BytesToYAMLDocs] and [YAMLDocsToJSON] to work with streams of documents separated by "---"
 //   - [YAMLMapSlice] to serialize and deserialize YAML with the order of keys maintained
 package yamlutils
 
@@ -4,8 +4,11 @@
 package yamlutils
 
 import (
+	"bytes"
 	json "encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/go-openapi/swag/jsonutils"
@@ -44,6 +47,67 @@ func BytesToYAMLDoc(data []byte) (any, error) {
 	return &document, nil
 }
 
+// BytesToYAMLDocs converts a byte slice into all the YAML documents it holds,
+// such as a stream of documents separated by "---".
+//
+// The documents are returned in order. Empty documents are skipped.
+//
+// Like [BytesToYAMLDoc], this function only supports documents that are objects.
+func BytesToYAMLDocs(data []byte) ([]any, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var documents []any
+	for index := 0; ; index++ {
+		document := new(yaml.Node)
+		err := decoder.Decode(document)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML document at index %d: %w: %w", index, err, ErrYAML)
+		}
+
+		if isEmptyYAMLDocument(document) {
+			continue
+		}
+
+		if len(document.Content) != 1 || document.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("only YAML documents that are objects are supported, at index %d: %w", index, ErrYAML)
+		}
+
+		documents = append(documents, document)
+	}
+}
+
+// isEmptyYAMLDocument tells if a document is empty, e.g. a document with only comments.
+func isEmptyYAMLDocument(document *yaml.Node) bool {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return true
+	}
+
+	root := document.Content[0]
+
+	return len(document.Content) == 1 && root.Kind == yaml.ScalarNode && root.LongTag() == yamlNull && root.Value == ""
+}
+
+// YAMLDocsToJSON converts several YAML documents into JSON bytes, one for each document.
+//
+// [YAMLDocsToJSON] is typically called after [BytesToYAMLDocs].
+func YAMLDocsToJSON(documents []any) ([]json.RawMessage, error) {
+	results := make([]json.RawMessage, 0, len(documents))
+
+	for index, document := range documents {
+		result, err := YAMLToJSON(document)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert YAML document at index %d: %w", index, err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 func yamlNode(root *yaml.Node) (any, error) {
 	switch root.Kind {
 	case yaml.DocumentNode:
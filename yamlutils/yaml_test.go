@@ -179,6 +179,64 @@ name: a string value
 	})
 }
 
+func TestBytesToYAMLDocs(t *testing.T) {
+	const stream = `---
+kind: ConfigMap
+name: first
+---
+# an empty document is skipped
+---
+kind: Secret
+name: second
+items: [1, 2]
+`
+
+	t.Run("should decode all documents in order", func(t *testing.T) {
+		docs, err := BytesToYAMLDocs([]byte(stream))
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+
+		t.Run("should convert every document to JSON", func(t *testing.T) {
+			jsonDocs, err := YAMLDocsToJSON(docs)
+			require.NoError(t, err)
+			require.Len(t, jsonDocs, 2)
+			assert.JSONEqBytes(t, []byte(`{"kind":"ConfigMap","name":"first"}`), jsonDocs[0])
+			assert.JSONEqBytes(t, []byte(`{"kind":"Secret","name":"second","items":[1,2]}`), jsonDocs[1])
+		})
+	})
+
+	t.Run("should decode a single document", func(t *testing.T) {
+		docs, err := BytesToYAMLDocs([]byte("description: 'object created'\n"))
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+	})
+
+	t.Run("should decode an empty stream", func(t *testing.T) {
+		docs, err := BytesToYAMLDocs(nil)
+		require.NoError(t, err)
+		assert.Empty(t, docs)
+	})
+
+	t.Run("should NOT decode a document that is not an object", func(t *testing.T) {
+		_, err := BytesToYAMLDocs([]byte("name: first\n---\n- name: hello\n"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrYAML)
+		assert.StringContainsT(t, err.Error(), "index 1")
+	})
+
+	t.Run("should NOT decode invalid YAML", func(t *testing.T) {
+		_, err := BytesToYAMLDocs([]byte("name: first\n---\nname:\tgreetings: hello\n"))
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrYAML)
+	})
+
+	t.Run("should NOT convert an invalid document to JSON", func(t *testing.T) {
+		_, err := YAMLDocsToJSON([]any{make(chan int)})
+		require.Error(t, err)
+		assert.StringContainsT(t, err.Error(), "index 0")
+	})
+}
+
 func TestWithYKey(t *testing.T) {
 	doc, err := BytesToYAMLDoc(fixtureWithYKey)
 	require.NoError(t, err)